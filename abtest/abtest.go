@@ -0,0 +1,345 @@
+// Package abtest runs A/B tests of campaign variants on random samples of a list and sends the winning
+// variant to the rest of the list.
+package abtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+type Metric string
+
+const (
+	ViewsMetric  Metric = "views"
+	ClicksMetric Metric = "clicks"
+)
+
+// Page size used when listing subscribers and chunk size of membership updates.
+const pageSize = 1000
+
+type Config struct {
+	// ID of the list to test against.
+	ListID int
+	// Campaign variants to test. Their lists are replaced with the sample lists.
+	Variants []listmonkgo.CreateCampaignParams
+	// Share of the list, between 0 and 1, that receives each variant.
+	SampleFraction float64
+	// How long to wait for engagement after the variants are sent.
+	Window time.Duration
+	// Metric that decides the winner. Defaults to views.
+	Metric Metric
+	// Interval to poll campaign statuses at while waiting for them to finish. Defaults to a minute.
+	PollInterval time.Duration
+	// Optional source of randomness for splitting the list.
+	Rand *rand.Rand
+}
+
+type VariantResult struct {
+	// ID of the campaign created for the variant.
+	CampaignID int
+	// ID of the temporary list the variant was sent to.
+	ListID int
+	// Number of subscribers in the sample.
+	Recipients int
+	// Value of the metric at the end of the window.
+	Score int
+}
+
+type Result struct {
+	Variants []VariantResult
+	// Index of the winning variant.
+	Winner int
+	// ID of the campaign that sent the winner to the remaining audience.
+	CampaignID int
+}
+
+// Returned by Run when the test fails while some of its campaigns may still be sending. Deleting the temporary
+// lists of those campaigns would detach them midway, so the lists are left for the caller to remove.
+type Error struct {
+	Err error
+	// IDs of the temporary lists that were not deleted.
+	Lists []int
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v (temporary lists %v were not deleted)", e.Err, e.Lists)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Temporary list and the campaign sent to it, zero until created.
+type temporaryList struct {
+	list     int
+	campaign int
+}
+
+type Test struct {
+	client *listmonkgo.Client
+	config Config
+}
+
+func New(client *listmonkgo.Client, config Config) *Test {
+	if len(config.Metric) == 0 {
+		config.Metric = ViewsMetric
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Minute
+	}
+	if config.Rand == nil {
+		config.Rand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	return &Test{client: client, config: config}
+}
+
+// Run the test end to end: split the list, send the variants, wait for the window, pick the winner, send it
+// to the remaining audience and delete the temporary lists once every campaign has finished. When the test
+// fails midway, only the lists of campaigns that are not sending are deleted and the rest are reported in an
+// *Error.
+func (t *Test) Run(ctx context.Context) (result *Result, err error) {
+	if len(t.config.Variants) < 2 {
+		return nil, errors.New("at least two variants are required")
+	}
+	if t.config.SampleFraction <= 0 || t.config.SampleFraction*float64(len(t.config.Variants)) >= 1 {
+		return nil, errors.New("samples of all variants must leave part of the list for the winner")
+	}
+
+	subscribers, err := t.subscribers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	size := int(float64(len(subscribers)) * t.config.SampleFraction)
+	if size == 0 {
+		return nil, fmt.Errorf("list %d is too small to sample", t.config.ListID)
+	}
+	t.config.Rand.Shuffle(len(subscribers), func(i, j int) {
+		subscribers[i], subscribers[j] = subscribers[j], subscribers[i]
+	})
+
+	lists := []temporaryList{}
+	defer func() {
+		// Cleanup runs even if the test fails midway or its context is cancelled
+		cleanup := context.WithoutCancel(ctx)
+		if err == nil {
+			for _, list := range lists {
+				t.client.DeleteList(cleanup, list.list)
+			}
+			return
+		}
+		if kept := t.cleanup(cleanup, lists); len(kept) > 0 {
+			result, err = nil, &Error{Err: err, Lists: kept}
+		}
+	}()
+
+	result = &Result{}
+	campaigns := []int{}
+	for i, variant := range t.config.Variants {
+		sample := subscribers[i*size : (i+1)*size]
+		list, err := t.list(ctx, fmt.Sprintf("A/B %s variant %d", variant.Name, i+1), sample)
+		if list != 0 {
+			lists = append(lists, temporaryList{list: list})
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		campaign, err := t.send(ctx, variant, list)
+		lists[len(lists)-1].campaign = campaign
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, campaign)
+		result.Variants = append(result.Variants, VariantResult{CampaignID: campaign, ListID: list, Recipients: len(sample)})
+	}
+	started := time.Now()
+
+	if err := wait(ctx, t.config.Window); err != nil {
+		return nil, err
+	}
+
+	scores, err := t.scores(ctx, campaigns, started)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result.Variants {
+		result.Variants[i].Score = scores[result.Variants[i].CampaignID]
+		if result.Variants[i].Score > result.Variants[result.Winner].Score {
+			result.Winner = i
+		}
+	}
+
+	winner := t.config.Variants[result.Winner]
+	remainder, err := t.list(ctx, fmt.Sprintf("A/B %s remainder", winner.Name), subscribers[len(t.config.Variants)*size:])
+	if remainder != 0 {
+		lists = append(lists, temporaryList{list: remainder})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.CampaignID, err = t.send(ctx, winner, remainder)
+	lists[len(lists)-1].campaign = result.CampaignID
+	if err != nil {
+		return nil, err
+	}
+	campaigns = append(campaigns, result.CampaignID)
+
+	// Deleting a list detaches it from its campaigns, so cleanup has to wait for every send to finish
+	if err := t.finish(ctx, campaigns); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Delete the lists whose campaigns were never started, finished or were cancelled, and return the lists whose
+// campaigns may still be sending.
+func (t *Test) cleanup(ctx context.Context, lists []temporaryList) []int {
+	kept := []int{}
+	for _, list := range lists {
+		if list.campaign != 0 {
+			campaign, err := t.client.GetCampaign(ctx, list.campaign, true)
+			if err != nil || !stopped(listmonkgo.CampaignStatus(campaign.Status)) {
+				kept = append(kept, list.list)
+				continue
+			}
+		}
+		if _, err := t.client.DeleteList(ctx, list.list); err != nil {
+			kept = append(kept, list.list)
+		}
+	}
+	return kept
+}
+
+func stopped(status listmonkgo.CampaignStatus) bool {
+	switch status {
+	case listmonkgo.CampaignStatusDraft, listmonkgo.CampaignStatusFinished, listmonkgo.CampaignStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (t *Test) subscribers(ctx context.Context) ([]int, error) {
+	ids := []int{}
+	for page := 1; ; page++ {
+		resp, err := t.client.GetSubscribers(ctx, &listmonkgo.GetSubscribersParams{ListID: []int{t.config.ListID}, Page: page, PerPage: pageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, subscriber := range resp.Results {
+			if subscriber.Status == listmonkgo.BlocklistedSubscriberStatus || unsubscribed(subscriber, t.config.ListID) {
+				continue
+			}
+			ids = append(ids, subscriber.ID)
+		}
+		if len(resp.Results) == 0 || page*pageSize >= resp.Total {
+			return ids, nil
+		}
+	}
+}
+
+func unsubscribed(subscriber listmonkgo.Subscriber, list int) bool {
+	for _, subscription := range subscriber.Lists {
+		if subscription.ID == list {
+			return subscription.SubscriptionStatus == "unsubscribed"
+		}
+	}
+	return false
+}
+
+// Create a temporary private list holding the given subscribers.
+func (t *Test) list(ctx context.Context, name string, subscribers []int) (int, error) {
+	list, err := t.client.CreateList(ctx, &listmonkgo.CreateListParams{
+		Name:  name,
+		Type:  listmonkgo.PrivateTypeListEntry,
+		Optin: listmonkgo.SingleOptinListEntry,
+		Tags:  []string{"abtest"},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for start := 0; start < len(subscribers); start += pageSize {
+		chunk := subscribers[start:min(start+pageSize, len(subscribers))]
+		_, err := t.client.UpdateListMemberships(ctx, &listmonkgo.UpdateListMembershipsParams{
+			IDs:           chunk,
+			Acion:         "add",
+			TargetListIDs: []int{list.ID},
+			Status:        "confirmed",
+		})
+		if err != nil {
+			return list.ID, err
+		}
+	}
+	return list.ID, nil
+}
+
+// Create a campaign for the variant against the list and start it.
+func (t *Test) send(ctx context.Context, variant listmonkgo.CreateCampaignParams, list int) (int, error) {
+	variant.Lists = []int{list}
+	variant.SendAt = time.Time{}
+	campaign, err := t.client.CreateCampaign(ctx, &variant)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := t.client.ChangeCampaignStatus(ctx, campaign.ID, listmonkgo.CampaignStatusRunning); err != nil {
+		return campaign.ID, err
+	}
+	return campaign.ID, nil
+}
+
+func (t *Test) scores(ctx context.Context, campaigns []int, from time.Time) (map[int]int, error) {
+	series, err := t.client.GetCampaignViews(ctx, &listmonkgo.GetCampaignViewsParams{
+		IDs:  campaigns,
+		Type: listmonkgo.CampaignStatType(t.config.Metric),
+		From: from.Add(-time.Hour),
+		To:   time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scores := map[int]int{}
+	for _, point := range series {
+		id, _ := point["campaign_id"].(float64)
+		count, _ := point["count"].(float64)
+		scores[int(id)] += int(count)
+	}
+	return scores, nil
+}
+
+// Wait until none of the campaigns are running anymore.
+func (t *Test) finish(ctx context.Context, campaigns []int) error {
+	for _, id := range campaigns {
+		for {
+			campaign, err := t.client.GetCampaign(ctx, id, true)
+			if err != nil {
+				return err
+			}
+			status := listmonkgo.CampaignStatus(campaign.Status)
+			if status == listmonkgo.CampaignStatusFinished || status == listmonkgo.CampaignStatusCancelled {
+				break
+			}
+			if err := wait(ctx, t.config.PollInterval); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func wait(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
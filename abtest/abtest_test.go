@@ -0,0 +1,200 @@
+package abtest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+	"github.com/canpacis/listmonk-go/abtest"
+)
+
+func TestRun(t *testing.T) {
+	var mu sync.Mutex
+	members := map[int][]int{}
+	campaignLists := map[int][]int{}
+	deleted := []int{}
+	nextList, nextCampaign := 100, 200
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/subscribers", func(w http.ResponseWriter, r *http.Request) {
+		results := []map[string]any{}
+		for i := 1; i <= 10; i++ {
+			results = append(results, map[string]any{"id": i, "status": "enabled", "lists": []map[string]any{{"id": 1, "subscription_status": "confirmed"}}})
+		}
+		results[9]["status"] = "blocklisted"
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"results": results, "total": 10}})
+	})
+	mux.HandleFunc("POST /api/lists", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		nextList++
+		fmt.Fprintf(w, `{"data": {"id": %d}}`, nextList)
+	})
+	mux.HandleFunc("PUT /api/subscribers/lists", func(w http.ResponseWriter, r *http.Request) {
+		var params listmonkgo.UpdateListMembershipsParams
+		json.NewDecoder(r.Body).Decode(&params)
+		mu.Lock()
+		defer mu.Unlock()
+		members[params.TargetListIDs[0]] = append(members[params.TargetListIDs[0]], params.IDs...)
+		w.Write([]byte(`{"data": true}`))
+	})
+	mux.HandleFunc("POST /api/campaigns", func(w http.ResponseWriter, r *http.Request) {
+		var params listmonkgo.CreateCampaignParams
+		json.NewDecoder(r.Body).Decode(&params)
+		mu.Lock()
+		defer mu.Unlock()
+		nextCampaign++
+		campaignLists[nextCampaign] = params.Lists
+		fmt.Fprintf(w, `{"data": {"id": %d, "subject": %q}}`, nextCampaign, params.Subject)
+	})
+	mux.HandleFunc("PUT /api/campaigns/{id}/status", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data": {"id": %s, "status": "running"}}`, r.PathValue("id"))
+	})
+	mux.HandleFunc("GET /api/campaigns/analytics/clicks", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [
+			{"campaign_id": 201, "count": 2, "timestamp": "2020-01-01T00:00:00Z"},
+			{"campaign_id": 202, "count": 3, "timestamp": "2020-01-01T00:00:00Z"},
+			{"campaign_id": 202, "count": 1, "timestamp": "2020-01-01T01:00:00Z"}
+		]}`))
+	})
+	mux.HandleFunc("GET /api/campaigns/{id}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data": {"id": %s, "status": "finished"}}`, r.PathValue("id"))
+	})
+	mux.HandleFunc("DELETE /api/lists/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscan(r.PathValue("id"), &id)
+		mu.Lock()
+		defer mu.Unlock()
+		deleted = append(deleted, id)
+		w.Write([]byte(`{"data": true}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	test := abtest.New(listmonkgo.New(listmonkgo.WithBaseURL(server.URL)), abtest.Config{
+		ListID: 1,
+		Variants: []listmonkgo.CreateCampaignParams{
+			{Name: "Launch", Subject: "A"},
+			{Name: "Launch", Subject: "B"},
+		},
+		SampleFraction: 0.2,
+		Window:         time.Millisecond,
+		Metric:         abtest.ClicksMetric,
+		PollInterval:   time.Millisecond,
+		Rand:           rand.New(rand.NewPCG(1, 2)),
+	})
+
+	result, err := test.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Winner != 1 || result.CampaignID != 203 {
+		t.Errorf("expected variant B to win and be sent as campaign 203, got %+v", result)
+	}
+	if result.Variants[0].Score != 2 || result.Variants[1].Score != 4 {
+		t.Errorf("unexpected scores %+v", result.Variants)
+	}
+	if len(members[101]) != 1 || len(members[102]) != 1 || len(members[103]) != 7 {
+		t.Errorf("unexpected split %v", members)
+	}
+	seen := map[int]bool{}
+	for _, ids := range members {
+		for _, id := range ids {
+			if seen[id] || id == 10 {
+				t.Errorf("subscriber %d is sampled twice or blocklisted", id)
+			}
+			seen[id] = true
+		}
+	}
+	if lists := campaignLists[203]; len(lists) != 1 || lists[0] != 103 {
+		t.Errorf("expected the winner to be sent to the remainder list, got %v", lists)
+	}
+	if len(deleted) != 3 {
+		t.Errorf("expected temporary lists to be deleted, got %v", deleted)
+	}
+}
+
+func TestRunKeepsListsOfSendingCampaigns(t *testing.T) {
+	var mu sync.Mutex
+	deleted := []int{}
+	nextList, nextCampaign := 100, 200
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/subscribers", func(w http.ResponseWriter, r *http.Request) {
+		results := []map[string]any{}
+		for i := 1; i <= 10; i++ {
+			results = append(results, map[string]any{"id": i, "status": "enabled"})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"results": results, "total": 10}})
+	})
+	mux.HandleFunc("POST /api/lists", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		nextList++
+		fmt.Fprintf(w, `{"data": {"id": %d}}`, nextList)
+	})
+	mux.HandleFunc("PUT /api/subscribers/lists", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": true}`))
+	})
+	mux.HandleFunc("POST /api/campaigns", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		nextCampaign++
+		fmt.Fprintf(w, `{"data": {"id": %d}}`, nextCampaign)
+	})
+	mux.HandleFunc("PUT /api/campaigns/{id}/status", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data": {"id": %s, "status": "running"}}`, r.PathValue("id"))
+	})
+	mux.HandleFunc("GET /api/campaigns/analytics/views", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "stats are unavailable"}`, http.StatusInternalServerError)
+	})
+	mux.HandleFunc("GET /api/campaigns/{id}", func(w http.ResponseWriter, r *http.Request) {
+		status := "finished"
+		if r.PathValue("id") == "201" {
+			status = "running"
+		}
+		fmt.Fprintf(w, `{"data": {"id": %s, "status": %q}}`, r.PathValue("id"), status)
+	})
+	mux.HandleFunc("DELETE /api/lists/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscan(r.PathValue("id"), &id)
+		mu.Lock()
+		defer mu.Unlock()
+		deleted = append(deleted, id)
+		w.Write([]byte(`{"data": true}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	test := abtest.New(listmonkgo.New(listmonkgo.WithBaseURL(server.URL)), abtest.Config{
+		ListID: 1,
+		Variants: []listmonkgo.CreateCampaignParams{
+			{Name: "Launch", Subject: "A"},
+			{Name: "Launch", Subject: "B"},
+		},
+		SampleFraction: 0.2,
+		Window:         time.Millisecond,
+		PollInterval:   time.Millisecond,
+	})
+
+	_, err := test.Run(context.Background())
+	var testErr *abtest.Error
+	if !errors.As(err, &testErr) {
+		t.Fatalf("expected an abtest error, got %v", err)
+	}
+	if len(testErr.Lists) != 1 || testErr.Lists[0] != 101 {
+		t.Errorf("expected the list of the running campaign to be kept, got %v", testErr.Lists)
+	}
+	if len(deleted) != 1 || deleted[0] != 102 {
+		t.Errorf("expected only the list of the finished campaign to be deleted, got %v", deleted)
+	}
+}
@@ -0,0 +1,166 @@
+package listmonkgo
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Page size used when listing campaigns for activity reports.
+const activityPageSize = 100
+
+// Engagement of a subscriber with the campaigns sent to one of their lists.
+type ListEngagement struct {
+	ListID             int
+	Name               string
+	SubscriptionStatus string
+	// Views of campaigns sent to the list.
+	Views int
+	// Clicks on links of campaigns sent to the list.
+	Clicks int
+}
+
+type SubscriberActivity struct {
+	Subscriber *Subscriber
+	// Total views and clicks across campaigns.
+	Views  int
+	Clicks int
+	// Views per campaign subject.
+	CampaignViews []ExportCampaignView
+	// Clicks per link.
+	LinkClicks []ExportLinkClick
+	// listmonk does not record when a subscriber opened a message or clicked a link, so these are the start
+	// times of the latest campaigns the subscriber viewed and clicked. Zero when there is no activity.
+	LastOpen  time.Time
+	LastClick time.Time
+	Bounces   []Bounce
+	// Number of bounces per type.
+	BounceCounts map[BounceType]int
+	// Engagement per list the subscriber is on.
+	Lists []ListEngagement
+}
+
+// Combine the profile, subscriptions, campaign views, link clicks and bounces of a subscriber into one report.
+// Views are attributed to campaigns by subject and clicks to campaigns whose body contains the link, among
+// the campaigns sent to the lists of the subscriber.
+func (c *Client) GetSubscriberActivity(ctx context.Context, id int) (*SubscriberActivity, error) {
+	subscriber, err := c.GetSubscriber(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	export, err := c.ExportSubscriber(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	bounces, err := c.GetSubscriberBounces(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := &SubscriberActivity{
+		Subscriber:    subscriber,
+		CampaignViews: export.CampaignViews,
+		LinkClicks:    export.LinkClicks,
+		Bounces:       bounces,
+		BounceCounts:  map[BounceType]int{},
+	}
+	for _, bounce := range bounces {
+		activity.BounceCounts[bounce.Type]++
+	}
+
+	lists := map[int]*ListEngagement{}
+	for _, subscription := range subscriber.Lists {
+		activity.Lists = append(activity.Lists, ListEngagement{
+			ListID:             subscription.ID,
+			Name:               subscription.Name,
+			SubscriptionStatus: subscription.SubscriptionStatus,
+		})
+	}
+	for i := range activity.Lists {
+		lists[activity.Lists[i].ListID] = &activity.Lists[i]
+	}
+
+	var campaigns []Campaign
+	if len(export.CampaignViews) > 0 || len(export.LinkClicks) > 0 {
+		campaigns, err = c.subscriberCampaigns(ctx, lists, len(export.LinkClicks) > 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, view := range export.CampaignViews {
+		activity.Views += view.Views
+		campaign := latestCampaign(campaigns, func(campaign *Campaign) bool {
+			return campaign.Subject == view.Campaign
+		})
+		if campaign == nil {
+			continue
+		}
+		activity.LastOpen = later(activity.LastOpen, campaign.StartedAt)
+		for _, list := range campaign.Lists {
+			if engagement, ok := lists[list.ID]; ok {
+				engagement.Views += view.Views
+			}
+		}
+	}
+
+	for _, click := range export.LinkClicks {
+		activity.Clicks += click.Clicks
+		campaign := latestCampaign(campaigns, func(campaign *Campaign) bool {
+			return strings.Contains(campaign.Body, click.URL)
+		})
+		if campaign == nil {
+			continue
+		}
+		activity.LastClick = later(activity.LastClick, campaign.StartedAt)
+		for _, list := range campaign.Lists {
+			if engagement, ok := lists[list.ID]; ok {
+				engagement.Clicks += click.Clicks
+			}
+		}
+	}
+
+	return activity, nil
+}
+
+// Campaigns that were sent to at least one of the lists. Bodies are only fetched when needed.
+func (c *Client) subscriberCampaigns(ctx context.Context, lists map[int]*ListEngagement, bodies bool) ([]Campaign, error) {
+	campaigns := []Campaign{}
+	for page := 1; ; page++ {
+		resp, err := c.GetCampaigns(ctx, &GetCampaignParams{Page: page, PerPage: activityPageSize, NoBody: !bodies})
+		if err != nil {
+			return nil, err
+		}
+		for _, campaign := range resp.Results {
+			if campaign.StartedAt.IsZero() {
+				continue
+			}
+			for _, list := range campaign.Lists {
+				if lists[list.ID] != nil {
+					campaigns = append(campaigns, campaign)
+					break
+				}
+			}
+		}
+		if len(resp.Results) == 0 || page*activityPageSize >= resp.Total {
+			return campaigns, nil
+		}
+	}
+}
+
+func latestCampaign(campaigns []Campaign, match func(campaign *Campaign) bool) *Campaign {
+	var latest *Campaign
+	for i := range campaigns {
+		if match(&campaigns[i]) && (latest == nil || campaigns[i].StartedAt.After(latest.StartedAt)) {
+			latest = &campaigns[i]
+		}
+	}
+	return latest
+}
+
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,62 @@
+package listmonkgo_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+func TestGetSubscriberActivity(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/subscribers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"id": 1, "email": "a@example.com", "lists": [
+			{"id": 1, "name": "News", "subscription_status": "confirmed"},
+			{"id": 2, "name": "Offers", "subscription_status": "unsubscribed"}
+		]}}`))
+	})
+	mux.HandleFunc("GET /api/subscribers/{id}/export", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"profile": [{"id": 1}], "subscriptions": [],
+			"campaign_views": [{"campaign": "March news", "views": 2}, {"campaign": "Spring sale", "views": 1}],
+			"link_clicks": [{"url": "https://example.com/sale", "clicks": 3}, {"url": "https://example.com/gone", "clicks": 1}]}`))
+	})
+	mux.HandleFunc("GET /api/subscribers/{id}/bounces", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{"id": 1, "type": "soft"}, {"id": 2, "type": "soft"}, {"id": 3, "type": "hard"}]}`))
+	})
+	mux.HandleFunc("GET /api/campaigns", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("no_body") != "false" {
+			t.Error("expected campaign bodies to match clicks")
+		}
+		w.Write([]byte(`{"data": {"total": 4, "results": [
+			{"id": 1, "subject": "March news", "body": "news", "lists": [{"id": 1}], "started_at": "2024-03-01T10:00:00Z"},
+			{"id": 2, "subject": "Spring sale", "body": "<a href=\"https://example.com/sale\">Sale</a>", "lists": [{"id": 1}, {"id": 2}], "started_at": "2024-04-01T10:00:00Z"},
+			{"id": 3, "subject": "Spring sale", "body": "https://example.com/sale", "lists": [{"id": 9}], "started_at": "2024-05-01T10:00:00Z"},
+			{"id": 4, "subject": "March news", "body": "draft", "lists": [{"id": 1}]}
+		]}}`))
+	})
+	client := createTestClient(t, mux)
+
+	activity, err := client.GetSubscriberActivity(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if activity.Views != 3 || activity.Clicks != 4 {
+		t.Errorf("unexpected totals %d views, %d clicks", activity.Views, activity.Clicks)
+	}
+	if activity.BounceCounts[listmonkgo.BounceTypeSoft] != 2 || activity.BounceCounts[listmonkgo.BounceTypeHard] != 1 {
+		t.Errorf("unexpected bounce counts %v", activity.BounceCounts)
+	}
+	april := time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+	if !activity.LastOpen.Equal(april) || !activity.LastClick.Equal(april) {
+		t.Errorf("unexpected last open %v and click %v", activity.LastOpen, activity.LastClick)
+	}
+	expected := []listmonkgo.ListEngagement{
+		{ListID: 1, Name: "News", SubscriptionStatus: "confirmed", Views: 3, Clicks: 3},
+		{ListID: 2, Name: "Offers", SubscriptionStatus: "unsubscribed", Views: 1, Clicks: 3},
+	}
+	if len(activity.Lists) != 2 || activity.Lists[0] != expected[0] || activity.Lists[1] != expected[1] {
+		t.Errorf("unexpected list engagement %+v", activity.Lists)
+	}
+}
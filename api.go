@@ -1,12 +1,20 @@
 package listmonkgo
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -105,6 +113,22 @@ type ExportProfile struct {
 	UpdatedAt  time.Time        `json:"updated_at"`
 }
 
+// listmonk exports the profile as an array with a single object.
+func (p *ExportProfile) UnmarshalJSON(data []byte) error {
+	type plain ExportProfile
+	if len(data) > 0 && data[0] == '[' {
+		profiles := []plain{}
+		if err := json.Unmarshal(data, &profiles); err != nil {
+			return err
+		}
+		if len(profiles) > 0 {
+			*p = ExportProfile(profiles[0])
+		}
+		return nil
+	}
+	return json.Unmarshal(data, (*plain)(p))
+}
+
 type ExportSubscription struct {
 	Name               string    `json:"name"`
 	Type               ListType  `json:"type"`
@@ -112,11 +136,25 @@ type ExportSubscription struct {
 	CreatedAt          time.Time `json:"created_at"`
 }
 
+// Views of campaigns with the same subject.
+type ExportCampaignView struct {
+	// Subject of the campaign.
+	Campaign string `json:"campaign"`
+	Views    int    `json:"views"`
+}
+
+// Clicks of the same link.
+type ExportLinkClick struct {
+	URL    string `json:"url"`
+	Clicks int    `json:"clicks"`
+}
+
 type ExportSubscriberResponse struct {
-	Profile       []ExportProfile      `json:"profile"`
+	Email         string               `json:"email"`
+	Profile       ExportProfile        `json:"profile"`
 	Subscriptions []ExportSubscription `json:"subscriptions"`
-	CampaignViews []any                `json:"campaign_views"`
-	LinkClicks    []any                `json:"link_clicks"`
+	CampaignViews []ExportCampaignView `json:"campaign_views"`
+	LinkClicks    []ExportLinkClick    `json:"link_clicks"`
 }
 
 // Export a specific subscriber data that gives profile, list subscriptions, campaign views
@@ -131,9 +169,9 @@ func (c *Client) ExportSubscriber(ctx context.Context, id int) (*ExportSubscribe
 }
 
 // Retrieve a subscriber bounce records.
-func (c *Client) GetSubscriberBounces(ctx context.Context, id int) ([]any, error) {
+func (c *Client) GetSubscriberBounces(ctx context.Context, id int) ([]Bounce, error) {
 	path := fmt.Sprintf("/api/subscribers/%d/bounces", id)
-	resp, err := request[Response[[]any]](c, ctx, "GET", path, nil)
+	resp, err := request[Response[[]Bounce]](c, ctx, "GET", path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -487,43 +525,80 @@ type SendTemplateParams struct {
 	SubscriberID int `json:"subscriber_id"`
 	// Multiple subscriber emails as alternative to subscriber_email.
 	SubscriberEmails []string `json:"subscriber_emails"`
-	//		Multiple subscriber IDs as an alternative to subscriber_id.
+	// Multiple subscriber IDs as an alternative to subscriber_id.
 	SubscriberIDs []int `json:"subscriber_ids"`
-	//	ID of the transactional template to be used for the message.
+	// ID of the transactional template to be used for the message.
 	TemplateID int `json:"template_id"`
-	//	Optional sender email.
+	// Optional sender email.
 	FromEmail string `json:"from_email"`
+	// Optional subject, overrides the subject of the template.
+	Subject string `json:"subject"`
 	// Optional nested JSON map. Available in the template as {{ .Tx.Data.* }}.
 	Data any `json:"data"`
-	//		Optional array of email headers.
+	// Optional email headers. Every value of a header is sent.
 	Headers http.Header `json:"headers"`
-	//	Messenger to send the message. Default is email.
+	// Messenger to send the message. Default is email.
 	Messenger string `json:"messenger"`
 	// Email format options include html, markdown, and plain.
 	ContentType TemplateContentType `json:"content_type"`
+	// Optional alternate plain text body for HTML messages.
+	AltBody string `json:"altbody"`
+	// Optional files to attach to the message. The message is sent as a multipart request when set.
+	Attachments []Attachment `json:"-"`
 }
 
-func (p *SendTemplateParams) MarshalJSON() ([]byte, error) {
-	headers := []map[string]any{}
-
+type Attachment struct {
+	// File name of the attachment.
+	Name string
+	// Content type of the attachment. Detected from the name if empty.
+	ContentType string
+	io.Reader
+}
+
+// Wire format of SendTemplateParams. Unset fields are omitted so that only one recipient field is sent.
+type sendTemplatePayload struct {
+	SubscriberEmail  string              `json:"subscriber_email,omitempty"`
+	SubscriberID     int                 `json:"subscriber_id,omitempty"`
+	SubscriberEmails []string            `json:"subscriber_emails,omitempty"`
+	SubscriberIDs    []int               `json:"subscriber_ids,omitempty"`
+	TemplateID       int                 `json:"template_id,omitempty"`
+	FromEmail        string              `json:"from_email,omitempty"`
+	Subject          string              `json:"subject,omitempty"`
+	Data             any                 `json:"data,omitempty"`
+	Headers          []map[string]string `json:"headers,omitempty"`
+	Messenger        string              `json:"messenger,omitempty"`
+	ContentType      TemplateContentType `json:"content_type,omitempty"`
+	AltBody          string              `json:"altbody,omitempty"`
+}
+
+// Encodes headers in listmonk's [{"key": "value"}] form, one entry per value in key order.
+func (p SendTemplateParams) MarshalJSON() ([]byte, error) {
+	payload := sendTemplatePayload{
+		SubscriberEmail:  p.SubscriberEmail,
+		SubscriberID:     p.SubscriberID,
+		SubscriberEmails: p.SubscriberEmails,
+		SubscriberIDs:    p.SubscriberIDs,
+		TemplateID:       p.TemplateID,
+		FromEmail:        p.FromEmail,
+		Subject:          p.Subject,
+		Data:             p.Data,
+		Messenger:        p.Messenger,
+		ContentType:      p.ContentType,
+		AltBody:          p.AltBody,
+	}
+
+	keys := make([]string, 0, len(p.Headers))
 	for key := range p.Headers {
-		mp := map[string]any{}
-		mp[key] = p.Headers.Get(key)
-		headers = append(headers, mp)
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range p.Headers[key] {
+			payload.Headers = append(payload.Headers, map[string]string{key: value})
+		}
 	}
 
-	return json.Marshal(map[string]any{
-		"subscriber_email":  p.SubscriberEmail,
-		"subscriber_id":     p.SubscriberID,
-		"subscriber_emails": p.SubscriberEmails,
-		"subscriber_ids":    p.SubscriberIDs,
-		"template_id":       p.TemplateID,
-		"from_email":        p.FromEmail,
-		"data":              p.Data,
-		"headers":           headers,
-		"messenger":         p.Messenger,
-		"content-type":      p.ContentType,
-	})
+	return json.Marshal(payload)
 }
 
 type GetImportStatisticsResponse struct {
@@ -590,7 +665,7 @@ func (c *Client) ImportSubscribers(ctx context.Context, params *ImportSubscriber
 	if err != nil {
 		return nil, err
 	}
-	resp, err := c.multipart(ctx, path, map[string]string{"params": string(config)}, map[string]io.Reader{"file": params.File})
+	resp, err := c.multipart(ctx, path, map[string]string{"params": string(config)}, []formFile{{Field: "file", Filename: "file", Reader: params.File}})
 	if err != nil {
 		return nil, err
 	}
@@ -625,20 +700,30 @@ const (
 	OptinCampaign   CampaignType = "optin"
 )
 
+type CampaignContentType string
+
+const (
+	RichtextCampaignContent CampaignContentType = "richtext"
+	HTMLCampaignContent     CampaignContentType = "html"
+	MarkdownCampaignContent CampaignContentType = "markdown"
+	PlainCampaignContent    CampaignContentType = "plain"
+	VisualCampaignContent   CampaignContentType = "visual"
+)
+
 type Campaign struct {
-	ID          int          `json:"id"`
-	TemplateID  int          `json:"template_id"`
-	UUID        uuid.UUID    `json:"uuid"`
-	Type        CampaignType `json:"type"`
-	Messenger   string       `json:"messenger"`
-	ContentType string       `json:"content_type"`
+	ID          int                 `json:"id"`
+	TemplateID  int                 `json:"template_id"`
+	UUID        uuid.UUID           `json:"uuid"`
+	Type        CampaignType        `json:"type"`
+	Messenger   string              `json:"messenger"`
+	ContentType CampaignContentType `json:"content_type"`
 
 	Name       string   `json:"name"`
 	Subject    string   `json:"subject"`
 	FromEmail  string   `json:"from_email"`
 	Body       string   `json:"body"`
 	BodySource string   `json:"body_source"`
-	AltBody    string   `json:"alt_body"`
+	AltBody    string   `json:"altbody"`
 	Status     string   `json:"status"`
 	Tags       []string `json:"tags"`
 
@@ -728,20 +813,24 @@ func (c *Client) GetCampaignPreview(ctx context.Context, id int) (string, error)
 	if err != nil {
 		return "", err
 	}
-	if resp.StatusCode != http.StatusOK {
-		decoder := json.NewDecoder(resp.Body)
-		data := new(ErrorResponse)
-		if err := decoder.Decode(data); err != nil {
-			return "", err
-		}
-		return "", errors.New(data.Message)
-	}
+	return decodeText(resp)
+}
 
-	body, err := io.ReadAll(resp.Body)
+// Render arbitrary content with a template, eg: a draft that is not saved yet. listmonk only previews in the
+// context of an existing campaign, so a saved campaign is required: it provides template fields such as
+// {{ .Campaign.Subject }}, its body is replaced by the given one and it is not modified.
+func (c *Client) PreviewCampaignContent(ctx context.Context, campaignID int, templateID int, contentType CampaignContentType, body string) (string, error) {
+	path := fmt.Sprintf("/api/campaigns/%d/preview", campaignID)
+	fields := map[string]string{
+		"template_id":  strconv.Itoa(templateID),
+		"content_type": string(contentType),
+		"body":         body,
+	}
+	resp, err := c.multipart(ctx, path, fields, nil)
 	if err != nil {
 		return "", err
 	}
-	return string(body), nil
+	return decodeText(resp)
 }
 
 // Retrieve stats of specified campaigns.
@@ -802,7 +891,7 @@ type CreateCampaignParams struct {
 	// Campaign type: 'regular' or 'optin'.
 	Type CampaignType `json:"type"`
 	// Content type: 'richtext', 'html', 'markdown', 'plain', 'visual'.
-	ContentType string `json:"content_type"`
+	ContentType CampaignContentType `json:"content_type"`
 	// Content body of campaign.
 	Body string `json:"body"`
 	// If content_type is visual, the JSON block source of the body.
@@ -810,7 +899,7 @@ type CreateCampaignParams struct {
 	// Alternate plain text body for HTML (and richtext) emails.
 	Altbody string `json:"altbody"`
 	// Timestamp to schedule campaign. Format: 'YYYY-MM-DDTHH:MM:SSZ'.
-	SendAt time.Time `json:"send_at"`
+	SendAt time.Time `json:"send_at,omitzero"`
 	// 'email' or a custom messenger defined in settings. Defaults to 'email' if not provided.
 	Messenger string `json:"messenger"`
 	// Template ID to use. Defaults to default template if not provided.
@@ -819,6 +908,16 @@ type CreateCampaignParams struct {
 	Tags []string `json:"tags"`
 	// Key-value pairs to send as SMTP headers. Example: [{"x-custom-header": "value"}].
 	Headers []map[string]any `json:"headers"`
+	// Media IDs to attach to the campaign.
+	Media []int `json:"media,omitempty"`
+	// State of the public archive.
+	Archive bool `json:"archive"`
+	// Archive template id. Defaults to 0.
+	ArchiveTemplateID int `json:"archive_template_id,omitempty"`
+	// Optional Metadata to use in campaign message or template. Eg: name, email.
+	ArchiveMeta map[string]any `json:"archive_meta,omitempty"`
+	// Name for page to be used in public archive URL
+	ArchiveSlug string `json:"archive_slug,omitempty"`
 }
 
 // Create a new campaign.
@@ -854,13 +953,36 @@ func (c *Client) UpdateCampaign(ctx context.Context, id int, params *CreateCampa
 	return resp.Data, nil
 }
 
+// Convert the body of a campaign from one content type to another. The converted body is returned
+// and the campaign itself is left untouched.
+func (c *Client) ConvertCampaignContent(ctx context.Context, id int, from, to CampaignContentType) (string, error) {
+	campaign, err := c.GetCampaign(ctx, id, false)
+	if err != nil {
+		return "", err
+	}
+
+	path := fmt.Sprintf("/api/campaigns/%d/content", id)
+	type params struct {
+		From CampaignContentType `json:"from"`
+		To   CampaignContentType `json:"to"`
+		Body string              `json:"body"`
+	}
+	resp, err := request[Response[string]](c, ctx, "POST", path, params{From: from, To: to, Body: campaign.Body})
+	if err != nil {
+		return "", err
+	}
+	return resp.Data, nil
+}
+
 type CampaignStatus string
 
 const (
+	CampaignStatusDraft     CampaignStatus = "draft"
 	CampaignStatusScheduled CampaignStatus = "scheduled"
 	CampaignStatusRunning   CampaignStatus = "running"
 	CampaignStatusPaused    CampaignStatus = "paused"
 	CampaignStatusCancelled CampaignStatus = "cancelled"
+	CampaignStatusFinished  CampaignStatus = "finished"
 )
 
 // Change status of a campaign.
@@ -955,27 +1077,109 @@ func (c *Client) GetMedia(ctx context.Context, id int) (*Media, error) {
 	return resp.Data, nil
 }
 
-type UploadMediaResponse struct {
-	ID        int       `json:"id"`
-	UUID      uuid.UUID `json:"uuid"`
-	Filename  string    `json:"filename"`
-	URI       string    `json:"uri"`
-	ThumbURI  string    `json:"thumb_uri"`
-	CreatedAt time.Time `json:"created_at"`
+// Stream the contents of a media file to the writer, eg: for backups. Returns the number of bytes written.
+func (c *Client) DownloadMedia(ctx context.Context, id int, w io.Writer) (int64, error) {
+	media, err := c.GetMedia(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return c.download(ctx, media, w)
+}
+
+func (c *Client) download(ctx context.Context, media *Media, w io.Writer) (int64, error) {
+	base, err := url.Parse(c.config.BaseURL)
+	if err != nil {
+		return 0, err
+	}
+	// Media URLs are absolute, relative ones are served by listmonk itself
+	location, err := base.Parse(media.URL)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", location.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	// Credentials are only sent to listmonk, not to external storage providers
+	if location.Host == base.Host {
+		req.Header.Set("Authorization", c.auth())
+	}
+
+	resp, err := c.config.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("downloading %s: %s", media.Filename, resp.Status)
+	}
+	return io.Copy(w, resp.Body)
 }
 
-// Upload media file.
-func (c *Client) UploadMedia(ctx context.Context, file io.Reader) (*UploadMediaResponse, error) {
+// Deprecated: Use Media, which UploadMedia returns.
+type UploadMediaResponse = Media
+
+// Returned by UploadMedia when a file fails validation.
+var ErrInvalidMedia = errors.New("invalid media file")
+
+type UploadMediaOptions struct {
+	// Optional content type, detected from the filename or the file contents when empty.
+	ContentType string
+	// Optional list of allowed file extensions, eg: ".png" or "png". Any extension is allowed when empty.
+	AllowedExtensions []string
+	// Optional maximum file size in bytes.
+	MaxSize int64
+}
+
+// Upload a media file with the given filename. Options are optional.
+func (c *Client) UploadMedia(ctx context.Context, name string, r io.Reader, opts *UploadMediaOptions) (*Media, error) {
+	if opts == nil {
+		opts = &UploadMediaOptions{}
+	}
+	name = filepath.Base(name)
+	if len(name) == 0 || name == "." || name == string(filepath.Separator) {
+		return nil, fmt.Errorf("%w: filename is required", ErrInvalidMedia)
+	}
+
+	ext := strings.ToLower(filepath.Ext(name))
+	if len(opts.AllowedExtensions) > 0 && !slices.ContainsFunc(opts.AllowedExtensions, func(allowed string) bool {
+		return allowed == "*" || strings.ToLower("."+strings.TrimPrefix(allowed, ".")) == ext
+	}) {
+		return nil, fmt.Errorf("%w: extension %q is not allowed", ErrInvalidMedia, ext)
+	}
+
+	// Read the whole file up front so the size is checked before anything is sent
+	reader := r
+	if opts.MaxSize > 0 {
+		reader = io.LimitReader(r, opts.MaxSize+1)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxSize > 0 && int64(len(data)) > opts.MaxSize {
+		return nil, fmt.Errorf("%w: file exceeds %d bytes", ErrInvalidMedia, opts.MaxSize)
+	}
+
+	contentType := opts.ContentType
+	if len(contentType) == 0 {
+		contentType = mime.TypeByExtension(ext)
+	}
+	if len(contentType) == 0 {
+		contentType = http.DetectContentType(data)
+	}
+
 	path := "/api/media"
-	resp, err := c.multipart(ctx, path, map[string]string{}, map[string]io.Reader{"file": file})
+	resp, err := c.multipart(ctx, path, map[string]string{}, []formFile{{Field: "file", Filename: name, ContentType: contentType, Reader: bytes.NewReader(data)}})
 	if err != nil {
 		return nil, err
 	}
-	data, err := decode[Response[*UploadMediaResponse]](resp)
+	media, err := decode[Response[*Media]](resp)
 	if err != nil {
 		return nil, err
 	}
-	return data.Data, nil
+	return media.Data, nil
 }
 
 // Delete uploaded media file.
@@ -988,14 +1192,24 @@ func (c *Client) DeleteMedia(ctx context.Context, id int) (bool, error) {
 	return resp.Data, nil
 }
 
+type TemplateType string
+
+const (
+	TemplateTypeCampaign       TemplateType = "campaign"
+	TemplateTypeCampaignVisual TemplateType = "campaign_visual"
+	TemplateTypeTx             TemplateType = "tx"
+)
+
 type Template struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Body      string    `json:"body"`
-	IsDefault bool      `json:"is_default"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	// BodySource string `json:"body_source"`
+	ID         int          `json:"id"`
+	Name       string       `json:"name"`
+	Type       TemplateType `json:"type"`
+	Subject    string       `json:"subject"`
+	Body       string       `json:"body"`
+	BodySource string       `json:"body_source"`
+	IsDefault  bool         `json:"is_default"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
 }
 
 // Retrieve all templates.
@@ -1025,53 +1239,79 @@ func (c *Client) GetTemplatePreview(ctx context.Context, id int) (string, error)
 	if err != nil {
 		return "", err
 	}
-	if resp.StatusCode != http.StatusOK {
-		decoder := json.NewDecoder(resp.Body)
-		data := new(ErrorResponse)
-		if err := decoder.Decode(data); err != nil {
-			return "", err
-		}
-		return "", errors.New(data.Message)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-	return string(body), nil
+	return decodeText(resp)
 }
 
 type CreateTemplateParams struct {
 	// Name of the template
 	Name string `json:"name"`
 	// Type of the template (campaign, campaign_visual, or tx)
-	Type string `json:"type"`
+	Type TemplateType `json:"type"`
 	// Subject line for the template (only for tx)
 	Subject string `json:"subject"`
 	// HTML body of the template
 	Body string `json:"body"`
 	// If type is campaign_visual, the JSON source for the email-builder tempalate
-	BodySource string `json:"body_source"`
+	BodySource string `json:"body_source,omitempty"`
+}
+
+// Template endpoints respond with a single template on recent listmonk versions and with a list on older ones.
+type templateResponse []Template
+
+func (t *templateResponse) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, (*[]Template)(t))
+	}
+	template := Template{}
+	if err := json.Unmarshal(data, &template); err != nil {
+		return err
+	}
+	*t = templateResponse{template}
+	return nil
+}
+
+func (t templateResponse) first() (*Template, error) {
+	if len(t) == 0 {
+		return nil, errors.New("listmonk returned no template")
+	}
+	return &t[0], nil
+}
+
+// Runs the pre-flight template check if it is enabled in the client config.
+func (c *Client) lintTemplate(params *CreateTemplateParams) error {
+	if !c.config.LintTemplates {
+		return nil
+	}
+	if issues := LintTemplate(params.Body, params.Type); hasLintErrors(issues) {
+		return &TemplateLintError{Issues: issues}
+	}
+	return nil
 }
 
 // Create a template.
 func (c *Client) CreateTemplate(ctx context.Context, params *CreateTemplateParams) (*Template, error) {
 	path := "/api/templates"
-	resp, err := request[Response[[]Template]](c, ctx, "POST", path, nil)
+	if err := c.lintTemplate(params); err != nil {
+		return nil, err
+	}
+	resp, err := request[Response[templateResponse]](c, ctx, "POST", path, params)
 	if err != nil {
 		return nil, err
 	}
-	return &resp.Data[0], nil
+	return resp.Data.first()
 }
 
 // Update a template.
-func (c *Client) UpdateTemplate(ctx context.Context, id int) (*Template, error) {
+func (c *Client) UpdateTemplate(ctx context.Context, id int, params *CreateTemplateParams) (*Template, error) {
 	path := fmt.Sprintf("/api/templates/%d", id)
-	resp, err := request[Response[[]Template]](c, ctx, "PUT", path, nil)
+	if err := c.lintTemplate(params); err != nil {
+		return nil, err
+	}
+	resp, err := request[Response[templateResponse]](c, ctx, "PUT", path, params)
 	if err != nil {
 		return nil, err
 	}
-	return &resp.Data[0], nil
+	return resp.Data.first()
 }
 
 // Set a template as the default.
@@ -1095,24 +1335,71 @@ func (c *Client) DeleteTemplate(ctx context.Context, id int) (bool, error) {
 }
 
 // Allows sending transactional messages to one or more subscribers via a preconfigured transactional template.
+// Attachments are sent as files of a multipart request along with the params as the data field.
 func (c *Client) SendTemplate(ctx context.Context, params *SendTemplateParams) (bool, error) {
 	path := "/api/tx"
-	resp, err := request[Response[bool]](c, ctx, "POST", path, params)
+	if len(params.Attachments) == 0 {
+		resp, err := request[Response[bool]](c, ctx, "POST", path, params)
+		if err != nil {
+			return false, err
+		}
+		return resp.Data, nil
+	}
+
+	data, err := json.Marshal(params)
 	if err != nil {
 		return false, err
 	}
-	return resp.Data, nil
+	files := []formFile{}
+	for _, attachment := range params.Attachments {
+		files = append(files, formFile{Field: "file", Filename: attachment.Name, ContentType: attachment.ContentType, Reader: attachment.Reader})
+	}
+	resp, err := c.multipart(ctx, path, map[string]string{"data": string(data)}, files)
+	if err != nil {
+		return false, err
+	}
+	decoded, err := decode[Response[bool]](resp)
+	if err != nil {
+		return false, err
+	}
+	return decoded.Data, nil
 }
 
+type BounceType string
+
+const (
+	BounceTypeSoft      BounceType = "soft"
+	BounceTypeHard      BounceType = "hard"
+	BounceTypeComplaint BounceType = "complaint"
+)
+
+// Where a bounce was recorded from.
+type BounceSource string
+
+const (
+	BounceSourceAPI      BounceSource = "api"
+	BounceSourceMailbox  BounceSource = "pop"
+	BounceSourceSES      BounceSource = "ses"
+	BounceSourceSendgrid BounceSource = "sendgrid"
+	BounceSourcePostmark BounceSource = "postmark"
+	BounceSourceMailgun  BounceSource = "mailgun"
+)
+
 type GetBouncesParams struct {
 	// Bounce record retrieval for particular campaign id
-	CompaignID int `url:"campaign_id"`
+	CampaignID int `url:"campaign_id"`
+	// Deprecated: Use CampaignID. Only used when CampaignID is not set.
+	CompaignID int `url:"-"`
 	// Page number for pagination.
 	Page int `url:"page"`
 	// Results per page. Set to 'all' to return all results.
 	PerPage int `url:"per_page"`
-	//
-	Source string `url:"source"`
+	// Bounce source to filter by.
+	Source BounceSource `url:"source"`
+	// Bounce type to filter by.
+	Type BounceType `url:"type,omitempty"`
+	// Subscriber ID to filter by.
+	SubscriberID int `url:"subscriber_id,omitempty"`
 	// Fields by which bounce records are ordered. Options:"email", "campaign_name", "source", "created_at".
 	OrderBy string `url:"order_by"`
 	// Sorts the result. Allowed values: 'asc','desc'
@@ -1121,8 +1408,8 @@ type GetBouncesParams struct {
 
 type Bounce struct {
 	ID             int            `json:"id"`
-	Type           string         `json:"type"`
-	Source         string         `json:"source"`
+	Type           BounceType     `json:"type"`
+	Source         BounceSource   `json:"source"`
 	Email          string         `json:"email"`
 	SubscriberID   int            `json:"subscriber_id"`
 	SubscriberUUID uuid.UUID      `json:"subscriber_uuid"`
@@ -1142,9 +1429,24 @@ type GetBouncesResponse struct {
 	PerPage int      `json:"per_page"`
 }
 
-// Retrieve bounce records.
+// Page size used when reading bounces to filter them by type or subscriber.
+const bouncesPageSize = 500
+
+// Results per page listmonk returns when none is given.
+const defaultBouncesPerPage = 20
+
+// Retrieve bounce records. listmonk does not filter bounces by type or subscriber, so when either filter is
+// set every page is read and filtered here, and the requested page and total are those of the matches.
 func (c *Client) GetBounces(ctx context.Context, params *GetBouncesParams) (*GetBouncesResponse, error) {
 	path := "/api/bounces"
+	if params != nil && params.CampaignID == 0 && params.CompaignID != 0 {
+		copied := *params
+		copied.CampaignID = copied.CompaignID
+		params = &copied
+	}
+	if params != nil && (len(params.Type) > 0 || params.SubscriberID != 0) {
+		return c.filterBounces(ctx, params)
+	}
 	resp, err := request[Response[*GetBouncesResponse]](c, ctx, "GET", path, params)
 	if err != nil {
 		return nil, err
@@ -1152,6 +1454,41 @@ func (c *Client) GetBounces(ctx context.Context, params *GetBouncesParams) (*Get
 	return resp.Data, nil
 }
 
+func (c *Client) filterBounces(ctx context.Context, params *GetBouncesParams) (*GetBouncesResponse, error) {
+	matched := []Bounce{}
+	query := *params
+	query.PerPage = bouncesPageSize
+	for query.Page = 1; ; query.Page++ {
+		resp, err := request[Response[*GetBouncesResponse]](c, ctx, "GET", "/api/bounces", &query)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Data == nil {
+			break
+		}
+		for _, bounce := range resp.Data.Results {
+			if (len(params.Type) == 0 || bounce.Type == params.Type) && (params.SubscriberID == 0 || bounce.SubscriberID == params.SubscriberID) {
+				matched = append(matched, bounce)
+			}
+		}
+		if len(resp.Data.Results) == 0 || query.Page*bouncesPageSize >= resp.Data.Total {
+			break
+		}
+	}
+
+	page, perPage := max(params.Page, 1), params.PerPage
+	if perPage == 0 {
+		perPage = defaultBouncesPerPage
+	}
+	start := min((page-1)*perPage, len(matched))
+	return &GetBouncesResponse{
+		Results: matched[start:min(start+perPage, len(matched))],
+		Total:   len(matched),
+		Page:    page,
+		PerPage: perPage,
+	}, nil
+}
+
 // Delete all bounce records.
 func (c *Client) DeleteAllBounces(ctx context.Context) (bool, error) {
 	path := "/api/bounces"
@@ -1187,3 +1524,45 @@ func (c *Client) DeleteBounce(ctx context.Context, id int) (bool, error) {
 	}
 	return resp.Data, nil
 }
+
+// Bounce reported to the generic bounce webhook. Either the email or the subscriber UUID is required.
+type BounceRecord struct {
+	Email          string    `json:"email,omitempty"`
+	SubscriberUUID uuid.UUID `json:"subscriber_uuid,omitzero"`
+	// Optional campaign the bounced message belongs to.
+	CampaignUUID uuid.UUID    `json:"campaign_uuid,omitzero"`
+	Type         BounceType   `json:"type"`
+	Source       BounceSource `json:"source"`
+	// Optional details of the bounce, such as the provider payload.
+	Meta      map[string]any `json:"meta,omitempty"`
+	CreatedAt time.Time      `json:"created_at,omitzero"`
+}
+
+// Check that a recipient and a known bounce type are set.
+func (r *BounceRecord) Validate() error {
+	if len(r.Email) == 0 && r.SubscriberUUID == uuid.Nil {
+		return errors.New("either email or subscriber_uuid is required")
+	}
+	switch r.Type {
+	case BounceTypeSoft, BounceTypeHard, BounceTypeComplaint:
+	default:
+		return fmt.Errorf("invalid bounce type %q", r.Type)
+	}
+	if len(r.Source) == 0 {
+		return errors.New("source is required")
+	}
+	return nil
+}
+
+// Record a bounce through the generic bounce webhook.
+func (c *Client) RecordBounce(ctx context.Context, record BounceRecord) (bool, error) {
+	if err := record.Validate(); err != nil {
+		return false, err
+	}
+	path := "/webhooks/bounce"
+	resp, err := request[Response[bool]](c, ctx, "POST", path, record)
+	if err != nil {
+		return false, err
+	}
+	return resp.Data, nil
+}
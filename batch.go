@@ -0,0 +1,208 @@
+package listmonkgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrBatcherClosed = errors.New("tx batcher is closed")
+
+// Returned by TxBatcher.Send when listmonk could not find the recipient of a message. The other messages
+// of its batch are still delivered.
+var ErrTxRecipientNotFound = errors.New("recipient not found")
+
+// Recipients listmonk lists in the error of a request it only partially delivered, eg:
+// "Subscriber (0: ada@example.com) not found.; Subscriber (7: ) not found."
+var txNotFound = regexp.MustCompile(`\((\d+): ([^)]*)\)`)
+
+type TxBatcherConfig struct {
+	// Maximum number of recipients in a single request. Defaults to 500.
+	MaxBatch int
+	// How long to collect recipients for a batch before sending it. Defaults to 100ms.
+	Window time.Duration
+	// Maximum number of concurrent requests. Defaults to 4.
+	MaxInFlight int
+}
+
+// Collects transactional messages that share a template and data and sends them as a single request
+// to many subscribers.
+type TxBatcher struct {
+	client   *Client
+	config   TxBatcherConfig
+	inflight chan struct{}
+
+	mu      sync.Mutex
+	pending map[string]*txBatch
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+type txBatch struct {
+	key     string
+	params  SendTemplateParams
+	emails  []string
+	ids     []int
+	waiters []chan error
+	timer   *time.Timer
+}
+
+func NewTxBatcher(client *Client, config TxBatcherConfig) *TxBatcher {
+	if config.MaxBatch <= 0 {
+		config.MaxBatch = 500
+	}
+	if config.Window <= 0 {
+		config.Window = 100 * time.Millisecond
+	}
+	if config.MaxInFlight <= 0 {
+		config.MaxInFlight = 4
+	}
+	return &TxBatcher{
+		client:   client,
+		config:   config,
+		inflight: make(chan struct{}, config.MaxInFlight),
+		pending:  map[string]*txBatch{},
+	}
+}
+
+// Queue a message to a single subscriber, set by subscriber_email or subscriber_id, and wait for the
+// request that carries it. Messages with equal params other than their recipient are batched together.
+// Cancelling the context stops waiting but does not remove the message from its batch. When listmonk
+// cannot find some recipients of a batch it delivers to the rest, so only the messages of those recipients
+// fail, with ErrTxRecipientNotFound.
+func (b *TxBatcher) Send(ctx context.Context, params *SendTemplateParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	if len(params.SubscriberEmails) > 0 || len(params.SubscriberIDs) > 0 {
+		return errors.New("batched messages must have a single recipient")
+	}
+	if len(params.Attachments) > 0 {
+		return errors.New("messages with attachments cannot be batched")
+	}
+
+	shared := *params
+	shared.SubscriberEmail, shared.SubscriberID = "", 0
+	encoded, err := json.Marshal(shared)
+	if err != nil {
+		return err
+	}
+	// Emails and IDs cannot be mixed in one request
+	key := "id:" + string(encoded)
+	if len(params.SubscriberEmail) > 0 {
+		key = "email:" + string(encoded)
+	}
+
+	done := make(chan error, 1)
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrBatcherClosed
+	}
+	batch, ok := b.pending[key]
+	if !ok {
+		batch = &txBatch{key: key, params: shared}
+		b.pending[key] = batch
+		batch.timer = time.AfterFunc(b.config.Window, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.flush(batch)
+		})
+	}
+	if len(params.SubscriberEmail) > 0 {
+		batch.emails = append(batch.emails, params.SubscriberEmail)
+	} else {
+		batch.ids = append(batch.ids, params.SubscriberID)
+	}
+	batch.waiters = append(batch.waiters, done)
+	if len(batch.waiters) >= b.config.MaxBatch {
+		b.flush(batch)
+	}
+	b.mu.Unlock()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Send a batch if it is still pending. Must be called with the lock held.
+func (b *TxBatcher) flush(batch *txBatch) {
+	if b.pending[batch.key] != batch {
+		return
+	}
+	delete(b.pending, batch.key)
+	batch.timer.Stop()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.inflight <- struct{}{}
+		defer func() { <-b.inflight }()
+
+		params := batch.params
+		params.SubscriberEmails = batch.emails
+		params.SubscriberIDs = batch.ids
+		ok, err := b.client.SendTemplate(context.Background(), &params)
+		if err == nil && !ok {
+			err = errors.New("listmonk did not accept the message")
+		}
+		var missing map[string]bool
+		if err != nil {
+			missing = txMissingRecipients(err.Error())
+		}
+		for i, waiter := range batch.waiters {
+			if len(missing) == 0 {
+				waiter <- err
+				continue
+			}
+			recipient := ""
+			if len(batch.emails) > 0 {
+				recipient = batch.emails[i]
+			} else {
+				recipient = strconv.Itoa(batch.ids[i])
+			}
+			if missing[strings.ToLower(recipient)] {
+				waiter <- fmt.Errorf("%w: %s", ErrTxRecipientNotFound, recipient)
+			} else {
+				waiter <- nil
+			}
+		}
+	}()
+}
+
+// Emails, lowercased, and IDs of the recipients listmonk could not find, empty if the error is not about
+// missing recipients.
+func txMissingRecipients(message string) map[string]bool {
+	missing := map[string]bool{}
+	for _, match := range txNotFound.FindAllStringSubmatch(message, -1) {
+		if email := strings.TrimSpace(match[2]); len(email) > 0 {
+			missing[strings.ToLower(email)] = true
+		} else {
+			missing[match[1]] = true
+		}
+	}
+	return missing
+}
+
+// Send every pending batch and wait for all requests to finish. Send fails once the batcher is closed.
+func (b *TxBatcher) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	for _, batch := range b.pending {
+		b.flush(batch)
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	return nil
+}
@@ -0,0 +1,146 @@
+package listmonkgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+func TestTxBatcher(t *testing.T) {
+	var mu sync.Mutex
+	requests := []map[string]any{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/tx", func(w http.ResponseWriter, r *http.Request) {
+		payload := map[string]any{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		requests = append(requests, payload)
+		mu.Unlock()
+		if payload["template_id"] == float64(9) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message": "template not found"}`))
+			return
+		}
+		w.Write([]byte(`{"data": true}`))
+	})
+	client := createTestClient(t, mux)
+	batcher := listmonkgo.NewTxBatcher(client, listmonkgo.TxBatcherConfig{MaxBatch: 3, Window: 20 * time.Millisecond})
+
+	sends := []*listmonkgo.SendTemplateParams{}
+	for i := range 4 {
+		sends = append(sends, &listmonkgo.SendTemplateParams{SubscriberEmail: fmt.Sprintf("%d@example.com", i), TemplateID: 1, Data: map[string]any{"sale": "spring"}})
+	}
+	sends = append(sends,
+		&listmonkgo.SendTemplateParams{SubscriberID: 7, TemplateID: 1, Data: map[string]any{"sale": "spring"}},
+		&listmonkgo.SendTemplateParams{SubscriberEmail: "other@example.com", TemplateID: 1, Data: map[string]any{"sale": "summer"}},
+		&listmonkgo.SendTemplateParams{SubscriberEmail: "missing@example.com", TemplateID: 9},
+	)
+
+	errs := make([]error, len(sends))
+	wg := sync.WaitGroup{}
+	for i, params := range sends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = batcher.Send(context.Background(), params)
+		}()
+	}
+	wg.Wait()
+	batcher.Close()
+
+	for i, err := range errs[:6] {
+		if err != nil {
+			t.Errorf("send %d: %v", i, err)
+		}
+	}
+	if errs[6] == nil || errs[6].Error() != "template not found" {
+		t.Errorf("expected the batch error to reach its sender, got %v", errs[6])
+	}
+
+	sizes := []int{}
+	for _, request := range requests {
+		if _, ok := request["subscriber_email"]; ok {
+			t.Errorf("expected batched recipients, got %v", request)
+		}
+		emails, _ := request["subscriber_emails"].([]any)
+		ids, _ := request["subscriber_ids"].([]any)
+		sizes = append(sizes, len(emails)+len(ids))
+	}
+	sort.Ints(sizes)
+	if fmt.Sprint(sizes) != "[1 1 1 1 3]" {
+		t.Errorf("unexpected batch sizes %v", sizes)
+	}
+
+	if err := batcher.Send(context.Background(), sends[0]); err != listmonkgo.ErrBatcherClosed {
+		t.Errorf("expected closed error, got %v", err)
+	}
+}
+
+func TestTxBatcherPartialDelivery(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/tx", func(w http.ResponseWriter, r *http.Request) {
+		payload := map[string]any{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		notFound := []string{}
+		if emails, ok := payload["subscriber_emails"].([]any); ok {
+			for _, email := range emails {
+				if strings.HasPrefix(strings.ToLower(email.(string)), "missing") {
+					notFound = append(notFound, fmt.Sprintf("Subscriber (0: %s) not found.", email))
+				}
+			}
+		}
+		if ids, ok := payload["subscriber_ids"].([]any); ok {
+			for _, id := range ids {
+				if id == float64(404) {
+					notFound = append(notFound, fmt.Sprintf("Subscriber (%v: ) not found.", id))
+				}
+			}
+		}
+		if len(notFound) > 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"message": strings.Join(notFound, "; ")})
+			return
+		}
+		w.Write([]byte(`{"data": true}`))
+	})
+	client := createTestClient(t, mux)
+	batcher := listmonkgo.NewTxBatcher(client, listmonkgo.TxBatcherConfig{Window: 20 * time.Millisecond})
+
+	sends := []*listmonkgo.SendTemplateParams{
+		{SubscriberEmail: "ada@example.com", TemplateID: 1},
+		{SubscriberEmail: "Missing@example.com", TemplateID: 1},
+		{SubscriberID: 7, TemplateID: 1},
+		{SubscriberID: 404, TemplateID: 1},
+	}
+	errs := make([]error, len(sends))
+	wg := sync.WaitGroup{}
+	for i, params := range sends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = batcher.Send(context.Background(), params)
+		}()
+	}
+	wg.Wait()
+	batcher.Close()
+
+	for _, i := range []int{0, 2} {
+		if errs[i] != nil {
+			t.Errorf("send %d: expected the found recipient to be delivered, got %v", i, errs[i])
+		}
+	}
+	for _, i := range []int{1, 3} {
+		if !errors.Is(errs[i], listmonkgo.ErrTxRecipientNotFound) {
+			t.Errorf("send %d: expected a not found error, got %v", i, errs[i])
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Package bouncehook receives bounce webhooks of email providers, normalizes them into bounce records and
+// forwards them to listmonk.
+package bouncehook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+// Returned by verification hooks to reject a request.
+var ErrUnauthorized = errors.New("webhook request could not be verified")
+
+// Header that carries the shared secret. The secret query parameter is accepted as well since not every
+// provider can set headers.
+const SecretHeader = "X-Webhook-Secret"
+
+// Maximum accepted size of a webhook payload.
+const maxBodySize = 1 << 20
+
+// Maximum age of a Mailgun signature, older requests are rejected as replays.
+const mailgunMaxAge = 5 * time.Minute
+
+// Host of the SNS endpoint of a region, the only host subscribe URLs may point to.
+var snsHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com$`)
+
+type Config struct {
+	// Shared secret that requests must carry in the X-Webhook-Secret header or the secret query parameter.
+	// Either the secret or a verification hook is required.
+	Secret string
+	// Verification of the raw request before it is parsed, eg: checking the provider signature. Return
+	// ErrUnauthorized or any other error to reject the request.
+	Verify func(provider Provider, r *http.Request, body []byte) error
+	// Client used to confirm SNS subscriptions. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Called when a request fails, eg: for logging.
+	OnError func(provider Provider, err error)
+}
+
+// Serves provider webhooks at paths ending with the provider name, eg: /bounces/ses or /bounces/mailgun.
+type Handler struct {
+	client *listmonkgo.Client
+	config Config
+}
+
+func New(client *listmonkgo.Client, config Config) (*Handler, error) {
+	if len(config.Secret) == 0 && config.Verify == nil {
+		return nil, errors.New("either a secret or a verification hook is required")
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	return &Handler{client: client, config: config}, nil
+}
+
+// Handler for the webhooks of a single provider regardless of the request path.
+func (h *Handler) Provider(provider Provider) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.serve(provider, w, r)
+	})
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.serve(Provider(path.Base(r.URL.Path)), w, r)
+}
+
+func (h *Handler) serve(provider Provider, w http.ResponseWriter, r *http.Request) {
+	status, err := h.handle(provider, w, r)
+	if err != nil {
+		if h.config.OnError != nil {
+			h.config.OnError(provider, err)
+		}
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) handle(provider Provider, w http.ResponseWriter, r *http.Request) (int, error) {
+	if r.Method != http.MethodPost {
+		return http.StatusMethodNotAllowed, fmt.Errorf("unexpected method %s", r.Method)
+	}
+	switch provider {
+	case SES, SendGrid, Postmark, Mailgun:
+	default:
+		return http.StatusNotFound, fmt.Errorf("unknown provider %q", provider)
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	if len(h.config.Secret) > 0 {
+		secret := r.Header.Get(SecretHeader)
+		if len(secret) == 0 {
+			secret = r.URL.Query().Get("secret")
+		}
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(h.config.Secret)) != 1 {
+			return http.StatusUnauthorized, ErrUnauthorized
+		}
+	}
+	if h.config.Verify != nil {
+		if err := h.config.Verify(provider, r, body); err != nil {
+			return http.StatusUnauthorized, err
+		}
+	}
+
+	if provider == SES {
+		if err := h.confirm(r.Context(), body); err != nil {
+			return http.StatusBadGateway, err
+		}
+	}
+
+	records, err := Parse(provider, body)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	for _, record := range records {
+		if _, err := h.client.RecordBounce(r.Context(), record); err != nil {
+			return http.StatusBadGateway, fmt.Errorf("%s: %w", record.Email, err)
+		}
+	}
+	return http.StatusOK, nil
+}
+
+// Confirm an SNS subscription by visiting its subscribe URL. Other messages are ignored.
+func (h *Handler) confirm(ctx context.Context, body []byte) error {
+	envelope := snsMessage{}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Type != "SubscriptionConfirmation" {
+		return nil
+	}
+
+	// Only SNS endpoints are visited so that the receiver cannot be used to make arbitrary requests
+	subscribe, err := url.Parse(envelope.SubscribeURL)
+	if err != nil || subscribe.Scheme != "https" || !snsHost.MatchString(subscribe.Hostname()) {
+		return fmt.Errorf("invalid SNS subscribe URL %q", envelope.SubscribeURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", subscribe.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := h.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("confirming SNS subscription: %s", resp.Status)
+	}
+	return nil
+}
+
+// Verification hook that checks the signature Mailgun adds to its webhook payloads with the webhook
+// signing key, and rejects signatures older than five minutes. Requests of other providers cannot be
+// verified and are rejected, so a hook that serves them as well has to check them itself and delegate
+// Mailgun requests to this one.
+func VerifyMailgun(signingKey string) func(provider Provider, r *http.Request, body []byte) error {
+	return func(provider Provider, r *http.Request, body []byte) error {
+		if provider != Mailgun {
+			return ErrUnauthorized
+		}
+		payload := mailgunPayload{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return err
+		}
+
+		mac := hmac.New(sha256.New, []byte(signingKey))
+		mac.Write([]byte(payload.Signature.Timestamp + payload.Signature.Token))
+		expected := hex.EncodeToString(mac.Sum(nil))
+		if !hmac.Equal([]byte(expected), []byte(payload.Signature.Signature)) {
+			return ErrUnauthorized
+		}
+
+		timestamp, err := strconv.ParseInt(payload.Signature.Timestamp, 10, 64)
+		if err != nil {
+			return ErrUnauthorized
+		}
+		if age := time.Since(time.Unix(timestamp, 0)); age > mailgunMaxAge || age < -mailgunMaxAge {
+			return ErrUnauthorized
+		}
+		return nil
+	}
+}
@@ -0,0 +1,142 @@
+package bouncehook_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+	"github.com/canpacis/listmonk-go/bouncehook"
+)
+
+func mailgunPayload(key, event, severity string, signedAt time.Time) string {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(timestamp + "abc"))
+	signature := hex.EncodeToString(mac.Sum(nil))
+	return fmt.Sprintf(`{"signature": {"timestamp": %q, "token": "abc", "signature": %q},
+		"event-data": {"event": %q, "severity": %q, "recipient": "mg@example.com", "timestamp": 1700000000.5}}`, timestamp, signature, event, severity)
+}
+
+func TestHandler(t *testing.T) {
+	records := []listmonkgo.BounceRecord{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /webhooks/bounce", func(w http.ResponseWriter, r *http.Request) {
+		record := listmonkgo.BounceRecord{}
+		json.NewDecoder(r.Body).Decode(&record)
+		records = append(records, record)
+		w.Write([]byte(`{"data": true}`))
+	})
+	listmonk := httptest.NewServer(mux)
+	defer listmonk.Close()
+
+	client := listmonkgo.New(listmonkgo.WithBaseURL(listmonk.URL))
+	if _, err := bouncehook.New(client, bouncehook.Config{}); err == nil {
+		t.Error("expected a handler without authentication to be rejected")
+	}
+	verifyMailgun := bouncehook.VerifyMailgun("mailgun-key")
+	handler, err := bouncehook.New(client, bouncehook.Config{
+		Secret: "s3cret",
+		Verify: func(provider bouncehook.Provider, r *http.Request, body []byte) error {
+			if provider != bouncehook.Mailgun {
+				return nil
+			}
+			return verifyMailgun(provider, r, body)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	sesMessage, _ := json.Marshal(`{"notificationType": "Bounce", "bounce": {"bounceType": "Permanent",
+		"bouncedRecipients": [{"emailAddress": "ses@example.com"}], "timestamp": "2024-01-02T03:04:05Z"},
+		"mail": {"headers": [{"name": "X-Listmonk-Campaign", "value": "0b0e5d2e-3f53-4b35-8a3c-6f1b1d2c3e4f"}]}}`)
+	tests := []struct {
+		path   string
+		body   string
+		status int
+	}{
+		{"/bounces/ses", `{"Type": "Notification", "Message": ` + string(sesMessage) + `}`, http.StatusOK},
+		{"/bounces/sendgrid", `[{"email": "sg@example.com", "event": "bounce", "type": "blocked", "timestamp": 1700000000},
+			{"email": "open@example.com", "event": "open"},
+			{"email": "spam@example.com", "event": "spamreport"}]`, http.StatusOK},
+		{"/bounces/postmark", `{"RecordType": "Bounce", "Type": "HardBounce", "Email": "pm@example.com"}`, http.StatusOK},
+		{"/bounces/mailgun", mailgunPayload("mailgun-key", "failed", "temporary", time.Now()), http.StatusOK},
+		{"/bounces/mailgun", mailgunPayload("wrong-key", "failed", "permanent", time.Now()), http.StatusUnauthorized},
+		{"/bounces/mailgun", mailgunPayload("mailgun-key", "failed", "permanent", time.Now().Add(-time.Hour)), http.StatusUnauthorized},
+		{"/bounces/ses", `{"Type": "SubscriptionConfirmation", "SubscribeURL": "http://localhost/confirm"}`, http.StatusBadGateway},
+		{"/bounces/ses", `{"Type": "SubscriptionConfirmation", "SubscribeURL": "https://bucket.s3.amazonaws.com/confirm"}`, http.StatusBadGateway},
+		{"/bounces/unknown", `{}`, http.StatusNotFound},
+	}
+	for _, test := range tests {
+		req, _ := http.NewRequest("POST", server.URL+test.path, strings.NewReader(test.body))
+		req.Header.Set(bouncehook.SecretHeader, "s3cret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.status {
+			t.Errorf("%s: expected status %d, got %d", test.path, test.status, resp.StatusCode)
+		}
+	}
+
+	resp, err := http.Post(server.URL+"/bounces/postmark?secret=wrong", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected a wrong secret to be rejected, got %d", resp.StatusCode)
+	}
+
+	summary := []string{}
+	for _, record := range records {
+		summary = append(summary, fmt.Sprintf("%s %s %s", record.Source, record.Type, record.Email))
+	}
+	expected := []string{
+		"ses hard ses@example.com",
+		"sendgrid soft sg@example.com",
+		"sendgrid complaint spam@example.com",
+		"postmark hard pm@example.com",
+		"mailgun soft mg@example.com",
+	}
+	if strings.Join(summary, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected records:\n%s", strings.Join(summary, "\n"))
+	}
+	if records[0].CampaignUUID.String() != "0b0e5d2e-3f53-4b35-8a3c-6f1b1d2c3e4f" || records[0].Meta["notificationType"] != "Bounce" {
+		t.Errorf("expected the SES campaign and meta to be kept, got %+v", records[0])
+	}
+	if records[1].CreatedAt.Unix() != 1700000000 {
+		t.Errorf("expected the SendGrid timestamp to be kept, got %v", records[1].CreatedAt)
+	}
+}
+
+func TestVerifyMailgunRejectsOtherProviders(t *testing.T) {
+	handler, err := bouncehook.New(nil, bouncehook.Config{Verify: bouncehook.VerifyMailgun("mailgun-key")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/bounces/ses", "application/json",
+		strings.NewReader(`{"Type": "SubscriptionConfirmation", "SubscribeURL": "https://sns.us-east-1.amazonaws.com/confirm"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected an unverified SES request to be rejected, got %d", resp.StatusCode)
+	}
+}
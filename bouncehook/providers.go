@@ -0,0 +1,292 @@
+package bouncehook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+	"github.com/google/uuid"
+)
+
+type Provider string
+
+const (
+	// Amazon SES notifications delivered through SNS.
+	SES      Provider = "ses"
+	SendGrid Provider = "sendgrid"
+	Postmark Provider = "postmark"
+	Mailgun  Provider = "mailgun"
+)
+
+// Header listmonk sets on campaign messages.
+const campaignHeader = "X-Listmonk-Campaign"
+
+// Convert a webhook payload of the provider into bounce records. Events that are not bounces or complaints
+// are skipped.
+func Parse(provider Provider, body []byte) ([]listmonkgo.BounceRecord, error) {
+	switch provider {
+	case SES:
+		return parseSES(body)
+	case SendGrid:
+		return parseSendGrid(body)
+	case Postmark:
+		return parsePostmark(body)
+	case Mailgun:
+		return parseMailgun(body)
+	default:
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+}
+
+// Envelope of an SNS message.
+type snsMessage struct {
+	Type         string `json:"Type"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+type sesRecipient struct {
+	EmailAddress string `json:"emailAddress"`
+}
+
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	// Set instead of the notification type for configuration set events.
+	EventType string `json:"eventType"`
+	Bounce    struct {
+		BounceType        string         `json:"bounceType"`
+		BouncedRecipients []sesRecipient `json:"bouncedRecipients"`
+		Timestamp         time.Time      `json:"timestamp"`
+	} `json:"bounce"`
+	Complaint struct {
+		ComplainedRecipients []sesRecipient `json:"complainedRecipients"`
+		Timestamp            time.Time      `json:"timestamp"`
+	} `json:"complaint"`
+	Mail struct {
+		Headers []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"headers"`
+	} `json:"mail"`
+}
+
+func parseSES(body []byte) ([]listmonkgo.BounceRecord, error) {
+	envelope := snsMessage{}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Type != "Notification" {
+		return nil, nil
+	}
+
+	notification := sesNotification{}
+	if err := json.Unmarshal([]byte(envelope.Message), &notification); err != nil {
+		return nil, err
+	}
+	meta, err := decodeMeta([]byte(envelope.Message))
+	if err != nil {
+		return nil, err
+	}
+
+	campaign := uuid.Nil
+	for _, header := range notification.Mail.Headers {
+		if strings.EqualFold(header.Name, campaignHeader) {
+			campaign, _ = uuid.Parse(header.Value)
+		}
+	}
+
+	kind := notification.NotificationType
+	if len(kind) == 0 {
+		kind = notification.EventType
+	}
+	var (
+		bounceType listmonkgo.BounceType
+		recipients []sesRecipient
+		createdAt  time.Time
+	)
+	switch kind {
+	case "Bounce":
+		bounceType = listmonkgo.BounceTypeSoft
+		if notification.Bounce.BounceType == "Permanent" {
+			bounceType = listmonkgo.BounceTypeHard
+		}
+		recipients, createdAt = notification.Bounce.BouncedRecipients, notification.Bounce.Timestamp
+	case "Complaint":
+		bounceType = listmonkgo.BounceTypeComplaint
+		recipients, createdAt = notification.Complaint.ComplainedRecipients, notification.Complaint.Timestamp
+	default:
+		return nil, nil
+	}
+
+	records := []listmonkgo.BounceRecord{}
+	for _, recipient := range recipients {
+		records = append(records, listmonkgo.BounceRecord{
+			Email:        recipient.EmailAddress,
+			CampaignUUID: campaign,
+			Type:         bounceType,
+			Source:       listmonkgo.BounceSourceSES,
+			Meta:         meta,
+			CreatedAt:    createdAt,
+		})
+	}
+	return records, nil
+}
+
+type sendGridEvent struct {
+	Email     string `json:"email"`
+	Event     string `json:"event"`
+	Type      string `json:"type"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func parseSendGrid(body []byte) ([]listmonkgo.BounceRecord, error) {
+	raw := []json.RawMessage{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	records := []listmonkgo.BounceRecord{}
+	for _, data := range raw {
+		event := sendGridEvent{}
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, err
+		}
+
+		var bounceType listmonkgo.BounceType
+		switch {
+		case event.Event == "bounce" && event.Type == "blocked":
+			bounceType = listmonkgo.BounceTypeSoft
+		case event.Event == "bounce" || event.Event == "dropped":
+			bounceType = listmonkgo.BounceTypeHard
+		case event.Event == "spamreport":
+			bounceType = listmonkgo.BounceTypeComplaint
+		default:
+			continue
+		}
+
+		meta, err := decodeMeta(data)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, listmonkgo.BounceRecord{
+			Email:     event.Email,
+			Type:      bounceType,
+			Source:    listmonkgo.BounceSourceSendgrid,
+			Meta:      meta,
+			CreatedAt: unix(event.Timestamp),
+		})
+	}
+	return records, nil
+}
+
+type postmarkEvent struct {
+	RecordType string    `json:"RecordType"`
+	Type       string    `json:"Type"`
+	Email      string    `json:"Email"`
+	BouncedAt  time.Time `json:"BouncedAt"`
+}
+
+func parsePostmark(body []byte) ([]listmonkgo.BounceRecord, error) {
+	event := postmarkEvent{}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+
+	var bounceType listmonkgo.BounceType
+	switch {
+	case event.RecordType == "SpamComplaint" || event.Type == "SpamComplaint":
+		bounceType = listmonkgo.BounceTypeComplaint
+	case event.RecordType != "Bounce":
+		return nil, nil
+	case event.Type == "HardBounce", event.Type == "BadEmailAddress", event.Type == "ManuallyDeactivated":
+		bounceType = listmonkgo.BounceTypeHard
+	default:
+		bounceType = listmonkgo.BounceTypeSoft
+	}
+
+	meta, err := decodeMeta(body)
+	if err != nil {
+		return nil, err
+	}
+	return []listmonkgo.BounceRecord{{
+		Email:     event.Email,
+		Type:      bounceType,
+		Source:    listmonkgo.BounceSourcePostmark,
+		Meta:      meta,
+		CreatedAt: event.BouncedAt,
+	}}, nil
+}
+
+type mailgunPayload struct {
+	Signature mailgunSignature `json:"signature"`
+	EventData json.RawMessage  `json:"event-data"`
+}
+
+type mailgunSignature struct {
+	Timestamp string `json:"timestamp"`
+	Token     string `json:"token"`
+	Signature string `json:"signature"`
+}
+
+type mailgunEvent struct {
+	Event     string  `json:"event"`
+	Severity  string  `json:"severity"`
+	Recipient string  `json:"recipient"`
+	Timestamp float64 `json:"timestamp"`
+}
+
+func parseMailgun(body []byte) ([]listmonkgo.BounceRecord, error) {
+	payload := mailgunPayload{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if len(payload.EventData) == 0 {
+		return nil, errors.New("missing event-data")
+	}
+	event := mailgunEvent{}
+	if err := json.Unmarshal(payload.EventData, &event); err != nil {
+		return nil, err
+	}
+
+	var bounceType listmonkgo.BounceType
+	switch {
+	case event.Event == "failed" && event.Severity == "permanent":
+		bounceType = listmonkgo.BounceTypeHard
+	case event.Event == "failed":
+		bounceType = listmonkgo.BounceTypeSoft
+	case event.Event == "complained":
+		bounceType = listmonkgo.BounceTypeComplaint
+	default:
+		return nil, nil
+	}
+
+	meta, err := decodeMeta(payload.EventData)
+	if err != nil {
+		return nil, err
+	}
+	return []listmonkgo.BounceRecord{{
+		Email:     event.Recipient,
+		Type:      bounceType,
+		Source:    listmonkgo.BounceSourceMailgun,
+		Meta:      meta,
+		CreatedAt: unix(int64(event.Timestamp)),
+	}}, nil
+}
+
+func decodeMeta(data []byte) (map[string]any, error) {
+	meta := map[string]any{}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+func unix(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0).UTC()
+}
@@ -0,0 +1,175 @@
+package bouncepolicy_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+	"github.com/canpacis/listmonk-go/bouncepolicy"
+)
+
+func bounce(id, subscriber int, kind listmonkgo.BounceType, age time.Duration) listmonkgo.Bounce {
+	return listmonkgo.Bounce{ID: id, SubscriberID: subscriber, Email: fmt.Sprintf("%d@example.com", subscriber), Type: kind, CreatedAt: time.Now().Add(-age)}
+}
+
+func TestPolicy(t *testing.T) {
+	day := 24 * time.Hour
+	all := []listmonkgo.Bounce{
+		bounce(1, 1, listmonkgo.BounceTypeSoft, day),
+		bounce(2, 1, listmonkgo.BounceTypeSoft, 2*day),
+		bounce(3, 1, listmonkgo.BounceTypeSoft, 3*day),
+		bounce(4, 2, listmonkgo.BounceTypeHard, day),
+		bounce(5, 3, listmonkgo.BounceTypeComplaint, day),
+		bounce(6, 3, listmonkgo.BounceTypeSoft, day),
+		bounce(7, 4, listmonkgo.BounceTypeSoft, day),
+		bounce(8, 5, listmonkgo.BounceTypeSoft, day),
+		bounce(9, 5, listmonkgo.BounceTypeSoft, 10*day),
+		bounce(10, 5, listmonkgo.BounceTypeSoft, 20*day),
+	}
+
+	var mu sync.Mutex
+	bounces := slices.Clone(all)
+	calls := []string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/bounces", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		results := []listmonkgo.Bounce{}
+		if page == 1 {
+			results = bounces
+		}
+		json.NewEncoder(w).Encode(listmonkgo.Response[listmonkgo.GetBouncesResponse]{Data: listmonkgo.GetBouncesResponse{Results: results, Total: len(bounces)}})
+	})
+	mux.HandleFunc("DELETE /api/bounces", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		ids := r.URL.Query()["id"]
+		calls = append(calls, "DELETE bounces "+strings.Join(ids, ","))
+		bounces = slices.DeleteFunc(bounces, func(bounce listmonkgo.Bounce) bool {
+			return slices.Contains(ids, strconv.Itoa(bounce.ID))
+		})
+		w.Write([]byte(`{"data": true}`))
+	})
+	mux.HandleFunc("PUT /api/subscribers/lists", func(w http.ResponseWriter, r *http.Request) {
+		params := listmonkgo.UpdateListMembershipsParams{}
+		json.NewDecoder(r.Body).Decode(&params)
+		calls = append(calls, fmt.Sprintf("%s %v from %v", params.Acion, params.IDs, params.TargetListIDs))
+		w.Write([]byte(`{"data": true}`))
+	})
+	mux.HandleFunc("PUT /api/subscribers/{id}/blocklist", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "blocklist "+r.PathValue("id"))
+		w.Write([]byte(`{"data": true}`))
+	})
+	mux.HandleFunc("DELETE /api/subscribers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "delete "+r.PathValue("id"))
+		w.Write([]byte(`{"data": true}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := listmonkgo.New(listmonkgo.WithBaseURL(server.URL))
+
+	rules := []bouncepolicy.Rule{
+		{Name: "complaint", Type: listmonkgo.BounceTypeComplaint, Action: bouncepolicy.DeleteAction},
+		{Name: "hard", Type: listmonkgo.BounceTypeHard, Action: bouncepolicy.BlocklistAction},
+		{Name: "soft", Type: listmonkgo.BounceTypeSoft, Count: 3, Window: 7 * day, Action: bouncepolicy.UnsubscribeAction, Lists: []int{7}},
+	}
+	summary := func(report *bouncepolicy.Report) string {
+		lines := []string{}
+		for _, action := range report.Actions {
+			lines = append(lines, fmt.Sprintf("%s %s %d %v", action.Rule, action.Type, action.SubscriberID, action.BounceIDs))
+		}
+		return strings.Join(lines, "\n")
+	}
+	expected := "soft unsubscribe 1 [1 2 3]\nhard blocklist 2 [4]\ncomplaint delete 3 [5]"
+
+	dryRun, err := bouncepolicy.New(client, bouncepolicy.Config{Rules: rules, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := dryRun.Process(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary(report) != expected {
+		t.Errorf("unexpected dry run:\n%s", summary(report))
+	}
+	if len(calls) > 0 {
+		t.Errorf("expected a dry run to change nothing, got %v", calls)
+	}
+
+	path := filepath.Join(t.TempDir(), "actions.jsonl")
+	store, err := bouncepolicy.NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, err := bouncepolicy.New(client, bouncepolicy.Config{Rules: rules, Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err = policy.Process(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary(report) != expected || report.Deleted != 4 {
+		t.Errorf("unexpected report %d:\n%s", report.Deleted, summary(report))
+	}
+	expectedCalls := []string{"unsubscribe [1] from [7]", "blocklist 2", "delete 3", "DELETE bounces 1,2,3,4"}
+	if strings.Join(calls, "\n") != strings.Join(expectedCalls, "\n") {
+		t.Errorf("unexpected calls:\n%s", strings.Join(calls, "\n"))
+	}
+
+	// A rerun over the same bounces, eg: after their deletion failed, takes no actions again
+	mu.Lock()
+	bounces, calls = slices.Clone(all), nil
+	mu.Unlock()
+	reopened, err := bouncepolicy.NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, _ = bouncepolicy.New(client, bouncepolicy.Config{Rules: rules, Store: reopened})
+	report, err = policy.Process(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Actions) != 0 || report.Skipped != 3 {
+		t.Errorf("expected every action to be skipped, got %d actions and %d skipped", len(report.Actions), report.Skipped)
+	}
+	if strings.Join(calls, "\n") != "DELETE bounces 1,2,3,4,5" {
+		t.Errorf("unexpected calls:\n%s", strings.Join(calls, "\n"))
+	}
+}
+
+func TestNewValidatesRules(t *testing.T) {
+	_, err := bouncepolicy.New(nil, bouncepolicy.Config{Rules: []bouncepolicy.Rule{{Name: "soft", Type: listmonkgo.BounceTypeSoft, Action: bouncepolicy.UnsubscribeAction}}})
+	if err == nil {
+		t.Error("expected unsubscribe without lists to be rejected")
+	}
+
+	for _, bounceType := range []listmonkgo.BounceType{"", "Hard"} {
+		_, err = bouncepolicy.New(nil, bouncepolicy.Config{Rules: []bouncepolicy.Rule{{Name: "hard", Type: bounceType, Action: bouncepolicy.BlocklistAction}}})
+		if err == nil {
+			t.Errorf("expected bounce type %q to be rejected", bounceType)
+		}
+	}
+
+	for _, rule := range []bouncepolicy.Rule{
+		{Name: "count", Type: listmonkgo.BounceTypeHard, Action: bouncepolicy.BlocklistAction, Count: -1},
+		{Name: "window", Type: listmonkgo.BounceTypeHard, Action: bouncepolicy.BlocklistAction, Window: -time.Hour},
+	} {
+		_, err = bouncepolicy.New(nil, bouncepolicy.Config{Rules: []bouncepolicy.Rule{rule}})
+		if err == nil {
+			t.Errorf("expected a negative %s to be rejected", rule.Name)
+		}
+	}
+}
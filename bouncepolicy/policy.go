@@ -0,0 +1,290 @@
+// Package bouncepolicy suppresses subscribers based on their bounce records with user defined rules, eg:
+// unsubscribing after repeated soft bounces or blocklisting after a hard bounce.
+package bouncepolicy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+// Page size used when listing bounces and chunk size of bounce deletions.
+const pageSize = 500
+
+type ActionType string
+
+const (
+	// Unsubscribe the subscriber from the lists of the rule.
+	UnsubscribeAction ActionType = "unsubscribe"
+	BlocklistAction   ActionType = "blocklist"
+	DeleteAction      ActionType = "delete"
+)
+
+type Rule struct {
+	// Name of the rule, part of the idempotency key of its actions.
+	Name string
+	// Type of the bounces the rule counts.
+	Type listmonkgo.BounceType
+	// Number of bounces that trigger the rule. Defaults to 1.
+	Count int
+	// Period before now that bounces are counted in. Zero counts every bounce.
+	Window time.Duration
+	Action ActionType
+	// Lists to unsubscribe from, required for the unsubscribe action.
+	Lists []int
+}
+
+// Action taken, or planned in dry-run mode, for a subscriber.
+type Action struct {
+	// Idempotency key derived from the rule, the subscriber and the bounces that triggered the action.
+	Key          string     `json:"key"`
+	Rule         string     `json:"rule"`
+	Type         ActionType `json:"type"`
+	SubscriberID int        `json:"subscriber_id"`
+	Email        string     `json:"email"`
+	Lists        []int      `json:"lists,omitempty"`
+	// Bounces that triggered the action.
+	BounceIDs []int     `json:"bounce_ids"`
+	TakenAt   time.Time `json:"taken_at"`
+}
+
+type Report struct {
+	// Actions taken, or the actions that would be taken in dry-run mode.
+	Actions []Action
+	// Actions skipped since they were already taken by an earlier run.
+	Skipped int
+	// Bounces deleted after their actions were taken.
+	Deleted int
+}
+
+type Config struct {
+	// Rules to apply, in order. Only the first rule that triggers is applied to a subscriber.
+	Rules []Rule
+	// Report the actions without taking them, recording them or deleting bounces.
+	DryRun bool
+	// Store of the actions taken. Defaults to a memory store.
+	Store Store
+	// Interval to process bounces at in Run. Defaults to an hour.
+	Interval time.Duration
+	// Called with the report of every run and the error of failed runs in Run.
+	OnReport func(report *Report, err error)
+}
+
+type Policy struct {
+	client *listmonkgo.Client
+	config Config
+}
+
+func New(client *listmonkgo.Client, config Config) (*Policy, error) {
+	config.Rules = slices.Clone(config.Rules)
+	for i, rule := range config.Rules {
+		if len(rule.Name) == 0 {
+			return nil, fmt.Errorf("rule %d: name is required", i)
+		}
+		switch rule.Type {
+		case listmonkgo.BounceTypeSoft, listmonkgo.BounceTypeHard, listmonkgo.BounceTypeComplaint:
+		default:
+			return nil, fmt.Errorf("%s: unknown bounce type %q", rule.Name, rule.Type)
+		}
+		switch rule.Action {
+		case UnsubscribeAction:
+			if len(rule.Lists) == 0 {
+				return nil, fmt.Errorf("%s: lists are required to unsubscribe", rule.Name)
+			}
+		case BlocklistAction, DeleteAction:
+		default:
+			return nil, fmt.Errorf("%s: unknown action %q", rule.Name, rule.Action)
+		}
+		if rule.Count < 0 {
+			return nil, fmt.Errorf("%s: count must not be negative", rule.Name)
+		}
+		if rule.Window < 0 {
+			return nil, fmt.Errorf("%s: window must not be negative", rule.Name)
+		}
+		if rule.Count == 0 {
+			config.Rules[i].Count = 1
+		}
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryStore()
+	}
+	if config.Interval == 0 {
+		config.Interval = time.Hour
+	}
+	return &Policy{client: client, config: config}, nil
+}
+
+// Process bounces at the configured interval until the context is cancelled.
+func (p *Policy) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := p.Process(ctx)
+		if p.config.OnReport != nil {
+			p.config.OnReport(report, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Page through every bounce, apply the rules per subscriber and, unless in dry-run mode, take the actions
+// and delete the bounces that triggered them. Bounces that trigger no rule are kept for later runs.
+func (p *Policy) Process(ctx context.Context) (*Report, error) {
+	bounces, err := p.bounces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribers := []int{}
+	grouped := map[int][]listmonkgo.Bounce{}
+	for _, bounce := range bounces {
+		if bounce.SubscriberID == 0 {
+			continue
+		}
+		if _, ok := grouped[bounce.SubscriberID]; !ok {
+			subscribers = append(subscribers, bounce.SubscriberID)
+		}
+		grouped[bounce.SubscriberID] = append(grouped[bounce.SubscriberID], bounce)
+	}
+
+	now := time.Now()
+	report := &Report{}
+	processed := []int{}
+	for _, subscriber := range subscribers {
+		action := p.evaluate(grouped[subscriber], now)
+		if action == nil {
+			continue
+		}
+
+		seen, err := p.config.Store.Seen(ctx, action.Key)
+		if err != nil {
+			return report, err
+		}
+		if seen {
+			// The action was taken but its bounces may not have been deleted
+			report.Skipped++
+			processed = append(processed, action.BounceIDs...)
+			continue
+		}
+
+		report.Actions = append(report.Actions, *action)
+		if p.config.DryRun {
+			continue
+		}
+		if err := p.take(ctx, action); err != nil {
+			return report, fmt.Errorf("%s: subscriber %d: %w", action.Rule, action.SubscriberID, err)
+		}
+		if err := p.config.Store.Record(ctx, action); err != nil {
+			return report, err
+		}
+		// Bounces of deleted subscribers are deleted along with them
+		if action.Type != DeleteAction {
+			processed = append(processed, action.BounceIDs...)
+		}
+	}
+
+	if p.config.DryRun {
+		return report, nil
+	}
+	for start := 0; start < len(processed); start += pageSize {
+		chunk := processed[start:min(start+pageSize, len(processed))]
+		if _, err := p.client.DeleteBounces(ctx, chunk); err != nil {
+			return report, err
+		}
+		report.Deleted += len(chunk)
+	}
+	return report, nil
+}
+
+// Action of the first rule triggered by the bounces of a subscriber, nil if no rule triggers.
+func (p *Policy) evaluate(bounces []listmonkgo.Bounce, now time.Time) *Action {
+	for _, rule := range p.config.Rules {
+		ids := []int{}
+		for _, bounce := range bounces {
+			if bounce.Type != rule.Type {
+				continue
+			}
+			if rule.Window > 0 && bounce.CreatedAt.Before(now.Add(-rule.Window)) {
+				continue
+			}
+			ids = append(ids, bounce.ID)
+		}
+		if len(ids) < rule.Count {
+			continue
+		}
+
+		slices.Sort(ids)
+		return &Action{
+			Key:          key(rule.Name, bounces[0].SubscriberID, ids),
+			Rule:         rule.Name,
+			Type:         rule.Action,
+			SubscriberID: bounces[0].SubscriberID,
+			Email:        bounces[0].Email,
+			Lists:        rule.Lists,
+			BounceIDs:    ids,
+			TakenAt:      now,
+		}
+	}
+	return nil
+}
+
+func (p *Policy) take(ctx context.Context, action *Action) error {
+	var (
+		ok  bool
+		err error
+	)
+	switch action.Type {
+	case UnsubscribeAction:
+		ok, err = p.client.UpdateListMemberships(ctx, &listmonkgo.UpdateListMembershipsParams{
+			IDs:           []int{action.SubscriberID},
+			Acion:         "unsubscribe",
+			TargetListIDs: action.Lists,
+		})
+	case BlocklistAction:
+		ok, err = p.client.BlocklistSubscriber(ctx, action.SubscriberID)
+	case DeleteAction:
+		ok, err = p.client.DeleteSubscriber(ctx, action.SubscriberID)
+	}
+	if err == nil && !ok {
+		err = errors.New("listmonk did not apply the action")
+	}
+	return err
+}
+
+func (p *Policy) bounces(ctx context.Context) ([]listmonkgo.Bounce, error) {
+	bounces := []listmonkgo.Bounce{}
+	for page := 1; ; page++ {
+		resp, err := p.client.GetBounces(ctx, &listmonkgo.GetBouncesParams{Page: page, PerPage: pageSize, OrderBy: "created_at", Order: "asc"})
+		if err != nil {
+			return nil, err
+		}
+		bounces = append(bounces, resp.Results...)
+		if len(resp.Results) == 0 || page*pageSize >= resp.Total {
+			return bounces, nil
+		}
+	}
+}
+
+func key(rule string, subscriber int, bounces []int) string {
+	builder := new(strings.Builder)
+	for _, id := range bounces {
+		builder.WriteString(strconv.Itoa(id))
+		builder.WriteByte(',')
+	}
+	sum := sha256.Sum256([]byte(builder.String()))
+	return fmt.Sprintf("%s:%d:%s", rule, subscriber, hex.EncodeToString(sum[:8]))
+}
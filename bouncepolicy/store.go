@@ -0,0 +1,115 @@
+package bouncepolicy
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+)
+
+// Keeps the actions taken so that they are not taken again when the same bounces are processed.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Whether an action with the key was recorded.
+	Seen(ctx context.Context, key string) (bool, error)
+	// Record a taken action.
+	Record(ctx context.Context, action *Action) error
+}
+
+// Keeps actions in memory. Actions are forgotten when the process exits.
+type MemoryStore struct {
+	mu      sync.Mutex
+	actions map[string]*Action
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{actions: map[string]*Action{}}
+}
+
+func (s *MemoryStore) Seen(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.actions[key]
+	return ok, nil
+}
+
+func (s *MemoryStore) Record(ctx context.Context, action *Action) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *action
+	s.actions[action.Key] = &copied
+	return nil
+}
+
+// Appends actions as JSON lines to a file, which doubles as an audit log.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+	keys map[string]bool
+}
+
+// Open the log at the path, creating it if it does not exist.
+func NewFileStore(path string) (*FileStore, error) {
+	store := &FileStore{path: path, keys: map[string]bool{}}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		action := Action{}
+		if err := json.Unmarshal(scanner.Bytes(), &action); err != nil {
+			return nil, err
+		}
+		store.keys[action.Key] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *FileStore) Seen(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.keys[key], nil
+}
+
+func (s *FileStore) Record(ctx context.Context, action *Action) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(action)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	s.keys[action.Key] = true
+	return nil
+}
@@ -0,0 +1,124 @@
+package listmonkgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+const bouncesJSON = `[
+	{"id": 1, "type": "hard", "source": "ses", "email": "a@example.com", "subscriber_id": 3, "campaign": {"id": 9, "name": "News"}},
+	{"id": 2, "type": "soft", "source": "api", "email": "b@example.com", "subscriber_id": 4},
+	{"id": 3, "type": "complaint", "source": "postmark", "email": "a@example.com", "subscriber_id": 3}
+]`
+
+func TestGetSubscriberBounces(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/subscribers/{id}/bounces", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": ` + bouncesJSON + `}`))
+	})
+	client := createTestClient(t, mux)
+
+	bounces, err := client.GetSubscriberBounces(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bounces) != 3 || bounces[0].Type != listmonkgo.BounceTypeHard || bounces[0].Source != listmonkgo.BounceSourceSES || bounces[0].Campaign.ID != 9 {
+		t.Errorf("unexpected bounces %+v", bounces)
+	}
+}
+
+func TestGetBouncesFilters(t *testing.T) {
+	var query map[string][]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/bounces", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"data": {"results": ` + bouncesJSON + `, "total": 3}}`))
+	})
+	client := createTestClient(t, mux)
+
+	resp, err := client.GetBounces(context.Background(), &listmonkgo.GetBouncesParams{
+		CompaignID:   9,
+		Type:         listmonkgo.BounceTypeHard,
+		SubscriberID: 3,
+		Source:       listmonkgo.BounceSourceSES,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query["campaign_id"][0] != "9" || query["type"][0] != "hard" || query["subscriber_id"][0] != "3" || query["source"][0] != "ses" {
+		t.Errorf("unexpected query %v", query)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].ID != 1 {
+		t.Errorf("expected only the hard bounce of subscriber 3, got %+v", resp.Results)
+	}
+
+	if _, err := client.GetBounces(context.Background(), &listmonkgo.GetBouncesParams{CampaignID: 5, CompaignID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if query["campaign_id"][0] != "5" {
+		t.Errorf("expected CampaignID to take precedence, got %v", query["campaign_id"])
+	}
+	if _, ok := query["type"]; ok {
+		t.Errorf("expected no type filter, got %v", query)
+	}
+}
+
+func TestGetBouncesFilterPages(t *testing.T) {
+	bounces := []listmonkgo.Bounce{}
+	for id := 1; id <= 1200; id++ {
+		bounce := listmonkgo.Bounce{ID: id, Type: listmonkgo.BounceTypeSoft, SubscriberID: 3}
+		if id%2 == 1 {
+			bounce.Type = listmonkgo.BounceTypeHard
+		}
+		bounces = append(bounces, bounce)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/bounces", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		start := min((page-1)*perPage, len(bounces))
+		results := bounces[start:min(start+perPage, len(bounces))]
+		json.NewEncoder(w).Encode(listmonkgo.Response[listmonkgo.GetBouncesResponse]{Data: listmonkgo.GetBouncesResponse{Results: results, Total: len(bounces)}})
+	})
+	client := createTestClient(t, mux)
+
+	resp, err := client.GetBounces(context.Background(), &listmonkgo.GetBouncesParams{Type: listmonkgo.BounceTypeHard, Page: 2, PerPage: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Total != 600 || len(resp.Results) != 100 {
+		t.Fatalf("unexpected page of %d results out of %d", len(resp.Results), resp.Total)
+	}
+	if resp.Results[0].ID != 201 {
+		t.Errorf("expected the page to start at bounce 201, got %d", resp.Results[0].ID)
+	}
+}
+
+func TestRecordBounce(t *testing.T) {
+	var payload map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /webhooks/bounce", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"data": true}`))
+	})
+	client := createTestClient(t, mux)
+
+	ok, err := client.RecordBounce(context.Background(), listmonkgo.BounceRecord{Email: "a@example.com", Type: listmonkgo.BounceTypeHard, Source: listmonkgo.BounceSourceAPI})
+	if err != nil || !ok {
+		t.Fatal(ok, err)
+	}
+	encoded, _ := json.Marshal(payload)
+	if string(encoded) != `{"email":"a@example.com","source":"api","type":"hard"}` {
+		t.Errorf("unexpected payload %s", encoded)
+	}
+
+	if _, err := client.RecordBounce(context.Background(), listmonkgo.BounceRecord{Email: "a@example.com", Type: "bad", Source: listmonkgo.BounceSourceAPI}); err == nil {
+		t.Error("expected an invalid type to be rejected")
+	}
+}
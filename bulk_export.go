@@ -0,0 +1,272 @@
+package listmonkgo
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default page size of subscriber exports.
+const exportPageSize = 1000
+
+// Format of bulk subscriber exports.
+type BulkExportFormat string
+
+const (
+	// CSV with a header row.
+	BulkExportFormatCSV BulkExportFormat = "csv"
+	// Newline delimited JSON, one object per line.
+	BulkExportFormatNDJSON BulkExportFormat = "ndjson"
+)
+
+type ExportSubscribersParams struct {
+	// Optional SQL expression to filter subscribers.
+	Query string
+	// Optional IDs of lists to filter by.
+	ListID []int
+	// Dot separated attribute paths to export as columns, eg: "address.city".
+	Attributes []string
+	// Only export subscribers updated after the watermark of an earlier export.
+	UpdatedSince time.Time
+	// ID of the last subscriber of an earlier export. Subscribers updated at UpdatedSince are exported when
+	// their ID is greater.
+	UpdatedSinceID int
+	// Subscribers per request. Defaults to 1000.
+	PerPage int
+}
+
+type ExportSubscribersResult struct {
+	// Number of subscribers written.
+	Count int
+	// Latest updated_at of the written subscribers and ID of the last subscriber written, to pass as
+	// UpdatedSince and UpdatedSinceID to the next export. Equal to those params when nothing was written.
+	Watermark   time.Time
+	WatermarkID int
+}
+
+// Subscriber record of NDJSON exports.
+type exportedSubscriber struct {
+	ID         int                  `json:"id"`
+	UUID       string               `json:"uuid"`
+	Email      string               `json:"email"`
+	Name       string               `json:"name"`
+	Status     SubscriberStatus     `json:"status"`
+	Attributes map[string]any       `json:"attributes"`
+	Lists      []exportedMembership `json:"lists"`
+	CreatedAt  time.Time            `json:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+}
+
+type exportedMembership struct {
+	ID                 int    `json:"id"`
+	Name               string `json:"name"`
+	SubscriptionStatus string `json:"subscription_status"`
+}
+
+// Stream every subscriber matching the params to the writer as CSV or NDJSON, a batch at a time and ordered
+// by updated_at and ID. Batches are read after the last subscriber written rather than by page number, so
+// subscribers updated during the export are written again instead of shifting others out of the export.
+// CSV exports have a column per attribute path and a lists column of "id:status" pairs. NDJSON exports carry
+// all attributes unless attribute paths are given.
+func (c *Client) ExportSubscribers(ctx context.Context, params *ExportSubscribersParams, w io.Writer, format BulkExportFormat) (*ExportSubscribersResult, error) {
+	if params == nil {
+		params = &ExportSubscribersParams{}
+	}
+	if format != BulkExportFormatCSV && format != BulkExportFormatNDJSON {
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+	perPage := params.PerPage
+	if perPage == 0 {
+		perPage = exportPageSize
+	}
+
+	var writer *csv.Writer
+	encoder := json.NewEncoder(w)
+	if format == BulkExportFormatCSV {
+		writer = csv.NewWriter(w)
+		header := []string{"id", "uuid", "email", "name", "status", "created_at", "updated_at"}
+		header = append(header, params.Attributes...)
+		header = append(header, "lists")
+		if err := writer.Write(header); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ExportSubscribersResult{Watermark: params.UpdatedSince, WatermarkID: params.UpdatedSinceID}
+	scan := subscriberScan{query: params.Query, lists: params.ListID, perPage: perPage}
+	for {
+		batch, err := c.scanSubscribers(ctx, &scan, result.Watermark, result.WatermarkID)
+		if err != nil {
+			return result, err
+		}
+		if len(batch) == 0 {
+			return result, nil
+		}
+
+		for _, subscriber := range batch {
+			if format == BulkExportFormatCSV {
+				err = writer.Write(subscriberRow(&subscriber, params.Attributes))
+			} else {
+				err = encoder.Encode(subscriberRecord(&subscriber, params.Attributes))
+			}
+			if err != nil {
+				return result, err
+			}
+			result.Count++
+			result.Watermark, result.WatermarkID = subscriber.UpdatedAt, subscriber.ID
+		}
+		if writer != nil {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return result, err
+			}
+		}
+	}
+}
+
+// Filters and batch size of a keyset scan over subscribers in (updated_at, id) order.
+type subscriberScan struct {
+	query   string
+	lists   []int
+	perPage int
+}
+
+// Next batch of subscribers after the cursor in (updated_at, id) order, empty once the scan is done. listmonk
+// orders by a single column, so subscribers updated at the cursor time are read in ID order first. A full
+// page in updated_at order leaves out its last timestamp, whose remaining subscribers may be on the next page,
+// and reads that timestamp in ID order when the whole page shares it. A zero cursor time starts the scan.
+// id is not one of listmonk's sort fields, which sorts by ID when given any other field.
+func (c *Client) scanSubscribers(ctx context.Context, scan *subscriberScan, after time.Time, afterID int) ([]Subscriber, error) {
+	if !after.IsZero() {
+		ties, err := c.scanQuery(ctx, scan, "id", fmt.Sprintf("subscribers.updated_at = '%s' AND subscribers.id > %d", formatCursor(after), afterID))
+		if err != nil || len(ties) > 0 {
+			return ties, err
+		}
+	}
+
+	condition := ""
+	if !after.IsZero() {
+		condition = fmt.Sprintf("subscribers.updated_at > '%s'", formatCursor(after))
+	}
+	batch, err := c.scanQuery(ctx, scan, "updated_at", condition)
+	if err != nil {
+		return nil, err
+	}
+	if len(batch) == scan.perPage {
+		last := batch[len(batch)-1].UpdatedAt
+		batch = slices.DeleteFunc(batch, func(subscriber Subscriber) bool { return subscriber.UpdatedAt.Equal(last) })
+		if len(batch) == 0 {
+			return c.scanQuery(ctx, scan, "id", fmt.Sprintf("subscribers.updated_at = '%s'", formatCursor(last)))
+		}
+	}
+	slices.SortStableFunc(batch, func(a, b Subscriber) int {
+		if order := a.UpdatedAt.Compare(b.UpdatedAt); order != 0 {
+			return order
+		}
+		return a.ID - b.ID
+	})
+	return batch, nil
+}
+
+func (c *Client) scanQuery(ctx context.Context, scan *subscriberScan, orderBy string, condition string) ([]Subscriber, error) {
+	conditions := []string{}
+	if len(scan.query) > 0 {
+		conditions = append(conditions, "("+scan.query+")")
+	}
+	if len(condition) > 0 {
+		conditions = append(conditions, condition)
+	}
+	resp, err := c.GetSubscribers(ctx, &GetSubscribersParams{
+		Query:   strings.Join(conditions, " AND "),
+		ListID:  scan.lists,
+		OrderBy: orderBy,
+		Order:   "asc",
+		Page:    1,
+		PerPage: scan.perPage,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
+
+func formatCursor(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func subscriberRow(subscriber *Subscriber, paths []string) []string {
+	row := []string{
+		strconv.Itoa(subscriber.ID),
+		subscriber.UUID.String(),
+		subscriber.Email,
+		subscriber.Name,
+		string(subscriber.Status),
+		formatTime(subscriber.CreatedAt),
+		formatTime(subscriber.UpdatedAt),
+	}
+	for _, path := range paths {
+		value, _ := attribute(subscriber.Attributes, path)
+		switch value := value.(type) {
+		case nil:
+			row = append(row, "")
+		case string:
+			row = append(row, value)
+		default:
+			encoded, _ := json.Marshal(value)
+			row = append(row, string(encoded))
+		}
+	}
+
+	lists := []string{}
+	for _, list := range subscriber.Lists {
+		lists = append(lists, fmt.Sprintf("%d:%s", list.ID, list.SubscriptionStatus))
+	}
+	return append(row, strings.Join(lists, ";"))
+}
+
+func subscriberRecord(subscriber *Subscriber, paths []string) *exportedSubscriber {
+	record := &exportedSubscriber{
+		ID:         subscriber.ID,
+		UUID:       subscriber.UUID.String(),
+		Email:      subscriber.Email,
+		Name:       subscriber.Name,
+		Status:     subscriber.Status,
+		Attributes: subscriber.Attributes,
+		Lists:      []exportedMembership{},
+		CreatedAt:  subscriber.CreatedAt,
+		UpdatedAt:  subscriber.UpdatedAt,
+	}
+	if len(paths) > 0 {
+		record.Attributes = map[string]any{}
+		for _, path := range paths {
+			if value, ok := attribute(subscriber.Attributes, path); ok {
+				record.Attributes[path] = value
+			}
+		}
+	}
+	for _, list := range subscriber.Lists {
+		record.Lists = append(record.Lists, exportedMembership{ID: list.ID, Name: list.Name, SubscriptionStatus: list.SubscriptionStatus})
+	}
+	return record
+}
+
+// Value at a dot separated path of nested attributes.
+func attribute(attributes map[string]any, path string) (any, bool) {
+	var value any = attributes
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
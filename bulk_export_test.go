@@ -0,0 +1,234 @@
+package listmonkgo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+var (
+	tiesQueryPattern          = regexp.MustCompile(`subscribers\.updated_at = '([^']+)'(?: AND subscribers\.id > (\d+))?`)
+	afterQueryPattern         = regexp.MustCompile(`subscribers\.updated_at > '([^']+)'`)
+	subscriptionsQueryPattern = regexp.MustCompile(`subscriber_lists WHERE updated_at > '([^']+)' AND updated_at <= '([^']+)'\) AND subscribers\.id > (\d+)`)
+	latestSubscriptionPattern = regexp.MustCompile(`subscriber_lists ORDER BY updated_at DESC LIMIT 1`)
+)
+
+// Fake listmonk that filters subscribers by the keyset conditions of subscriber scans and the subscription
+// conditions of the change feed, and orders them by a single column like listmonk does. Like in listmonk,
+// subscription changes are only recorded in the subscription_updated_at of the lists. Other conditions of the
+// query are ignored.
+type subscribersServer struct {
+	mu          sync.Mutex
+	subscribers []listmonkgo.Subscriber
+	queries     []string
+	// Optional hook called before every request is served.
+	before func(server *subscribersServer)
+}
+
+func (s *subscribersServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.before != nil {
+		s.before(s)
+	}
+	query := r.URL.Query()
+	s.queries = append(s.queries, query.Get("query"))
+
+	results := []listmonkgo.Subscriber{}
+	if latestSubscriptionPattern.MatchString(query.Get("query")) {
+		latest := time.Time{}
+		for _, subscriber := range s.subscribers {
+			if updated := subscriptionUpdatedAt(subscriber); len(results) == 0 || updated.After(latest) {
+				results, latest = []listmonkgo.Subscriber{subscriber}, updated
+			}
+		}
+		json.NewEncoder(w).Encode(listmonkgo.Response[listmonkgo.GetSubscribersResponse]{Data: listmonkgo.GetSubscribersResponse{Results: results, Total: len(results)}})
+		return
+	}
+	for _, subscriber := range s.subscribers {
+		if match := subscriptionsQueryPattern.FindStringSubmatch(query.Get("query")); match != nil {
+			from, _ := time.Parse(time.RFC3339Nano, match[1])
+			to, _ := time.Parse(time.RFC3339Nano, match[2])
+			after, _ := strconv.Atoi(match[3])
+			changed := slices.ContainsFunc(subscriber.Lists, func(list listmonkgo.Subscription) bool {
+				return list.SubscriptionUpdatedAt.After(from) && !list.SubscriptionUpdatedAt.After(to)
+			})
+			if !changed || subscriber.ID <= after {
+				continue
+			}
+		} else if match := tiesQueryPattern.FindStringSubmatch(query.Get("query")); match != nil {
+			at, _ := time.Parse(time.RFC3339Nano, match[1])
+			after, _ := strconv.Atoi(match[2])
+			if !subscriber.UpdatedAt.Equal(at) || subscriber.ID <= after {
+				continue
+			}
+		} else if match := afterQueryPattern.FindStringSubmatch(query.Get("query")); match != nil {
+			at, _ := time.Parse(time.RFC3339Nano, match[1])
+			if !subscriber.UpdatedAt.After(at) {
+				continue
+			}
+		}
+		results = append(results, subscriber)
+	}
+	// Subscribers that share a timestamp keep the order they were added in
+	if query.Get("order_by") == "updated_at" {
+		slices.SortStableFunc(results, func(a, b listmonkgo.Subscriber) int { return a.UpdatedAt.Compare(b.UpdatedAt) })
+	} else {
+		slices.SortFunc(results, func(a, b listmonkgo.Subscriber) int { return a.ID - b.ID })
+	}
+	perPage, _ := strconv.Atoi(query.Get("per_page"))
+	total := len(results)
+	results = results[:min(perPage, len(results))]
+	json.NewEncoder(w).Encode(listmonkgo.Response[listmonkgo.GetSubscribersResponse]{Data: listmonkgo.GetSubscribersResponse{Results: results, Total: total}})
+}
+
+// Latest subscription change of a subscriber.
+func subscriptionUpdatedAt(subscriber listmonkgo.Subscriber) time.Time {
+	latest := time.Time{}
+	for _, list := range subscriber.Lists {
+		if list.SubscriptionUpdatedAt.After(latest) {
+			latest = list.SubscriptionUpdatedAt
+		}
+	}
+	return latest
+}
+
+// Add subscribers or replace the ones with the same ID. Must be called with the lock held from hooks.
+func (s *subscribersServer) put(subscribers ...listmonkgo.Subscriber) {
+	for _, subscriber := range subscribers {
+		i := slices.IndexFunc(s.subscribers, func(existing listmonkgo.Subscriber) bool { return existing.ID == subscriber.ID })
+		if i < 0 {
+			s.subscribers = append(s.subscribers, subscriber)
+		} else {
+			s.subscribers[i] = subscriber
+		}
+	}
+}
+
+func (s *subscribersServer) set(subscribers ...listmonkgo.Subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(subscribers...)
+}
+
+func TestExportSubscribers(t *testing.T) {
+	server := &subscribersServer{}
+	if err := json.Unmarshal([]byte(`[
+		{"id": 1, "uuid": "0b0e5d2e-3f53-4b35-8a3c-6f1b1d2c3e4f", "email": "a@example.com", "name": "A, B", "status": "enabled",
+			"attribs": {"address": {"city": "Paris"}, "age": 30}, "lists": [{"id": 1, "name": "News", "subscription_status": "confirmed"}, {"id": 2, "name": "Offers", "subscription_status": "unsubscribed"}],
+			"created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-02-01T00:00:00Z"},
+		{"id": 2, "email": "b@example.com", "status": "blocklisted", "attribs": {}, "lists": [], "updated_at": "2024-03-01T00:00:00Z"},
+		{"id": 3, "email": "c@example.com", "status": "enabled", "attribs": {"address": "unknown"}, "updated_at": "2024-02-15T00:00:00Z"}
+	]`), &server.subscribers); err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/subscribers", func(w http.ResponseWriter, r *http.Request) {
+		if query := r.URL.Query(); query.Get("order") != "asc" || query.Get("per_page") != "2" {
+			t.Errorf("unexpected query %v", query)
+		}
+		server.ServeHTTP(w, r)
+	})
+	client := createTestClient(t, mux)
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	params := &listmonkgo.ExportSubscribersParams{
+		Query:        "subscribers.name != ''",
+		Attributes:   []string{"address.city", "age"},
+		UpdatedSince: since,
+		PerPage:      2,
+	}
+
+	buffer := new(bytes.Buffer)
+	result, err := client.ExportSubscribers(context.Background(), params, buffer, listmonkgo.BulkExportFormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Join([]string{
+		"id,uuid,email,name,status,created_at,updated_at,address.city,age,lists",
+		`1,0b0e5d2e-3f53-4b35-8a3c-6f1b1d2c3e4f,a@example.com,"A, B",enabled,2024-01-01T00:00:00Z,2024-02-01T00:00:00Z,Paris,30,1:confirmed;2:unsubscribed`,
+		"3,00000000-0000-0000-0000-000000000000,c@example.com,,enabled,,2024-02-15T00:00:00Z,,,",
+		"2,00000000-0000-0000-0000-000000000000,b@example.com,,blocklisted,,2024-03-01T00:00:00Z,,,",
+		"",
+	}, "\n")
+	if buffer.String() != expected {
+		t.Errorf("unexpected csv:\n%s", buffer)
+	}
+	if result.Count != 3 || !result.Watermark.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) || result.WatermarkID != 2 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if server.queries[0] != "(subscribers.name != '') AND subscribers.updated_at = '2024-01-01T00:00:00Z' AND subscribers.id > 0" {
+		t.Errorf("unexpected query %q", server.queries[0])
+	}
+
+	buffer.Reset()
+	if _, err := client.ExportSubscribers(context.Background(), params, buffer, listmonkgo.BulkExportFormatNDJSON); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(lines))
+	}
+	record := map[string]any{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+	attributes, _ := json.Marshal(record["attributes"])
+	lists, _ := json.Marshal(record["lists"])
+	if string(attributes) != `{"address.city":"Paris","age":30}` || string(lists) != `[{"id":1,"name":"News","subscription_status":"confirmed"},{"id":2,"name":"Offers","subscription_status":"unsubscribed"}]` {
+		t.Errorf("unexpected record %s", lines[0])
+	}
+
+	if _, err := client.ExportSubscribers(context.Background(), nil, buffer, "zip"); err == nil {
+		t.Error("expected an unsupported format to be rejected")
+	}
+}
+
+func TestExportSubscribersKeyset(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := &subscribersServer{}
+	// Subscribers 3 to 5 share a timestamp and are not in ID order within it
+	for _, id := range []int{1, 2, 5, 4, 3} {
+		updated := base
+		if id > 2 {
+			updated = base.Add(time.Second)
+		}
+		server.put(listmonkgo.Subscriber{ID: id, Email: fmt.Sprintf("%d@example.com", id), UpdatedAt: updated})
+	}
+	requests := 0
+	server.before = func(server *subscribersServer) {
+		// Subscriber 1 is updated once it has been exported
+		if requests++; requests == 3 {
+			server.put(listmonkgo.Subscriber{ID: 1, Email: "1@example.com", UpdatedAt: base.Add(time.Minute)})
+		}
+	}
+	client := createTestClient(t, server)
+
+	buffer := new(bytes.Buffer)
+	result, err := client.ExportSubscribers(context.Background(), &listmonkgo.ExportSubscribersParams{PerPage: 2}, buffer, listmonkgo.BulkExportFormatNDJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		record := map[string]any{}
+		json.Unmarshal([]byte(line), &record)
+		ids = append(ids, fmt.Sprint(record["id"]))
+	}
+	if strings.Join(ids, ",") != "1,2,3,4,5,1" {
+		t.Errorf("expected every subscriber in order and the updated one again, got %v", ids)
+	}
+	if result.WatermarkID != 1 || !result.Watermark.Equal(base.Add(time.Minute)) {
+		t.Errorf("unexpected watermark %+v", result)
+	}
+}
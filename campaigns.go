@@ -0,0 +1,84 @@
+package listmonkgo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type CloneCampaignParams struct {
+	// Name of the new campaign. Defaults to "Copy of <source name>".
+	Name string
+	// Campaign email subject. Defaults to the source subject.
+	Subject string
+	// List IDs to send campaign to. Defaults to the source lists.
+	Lists []int
+	// Timestamp to schedule campaign. The source schedule is never copied.
+	SendAt time.Time
+	// Tags to mark campaign. Defaults to the source tags.
+	Tags []string
+	// Name for page to be used in public archive URL. The source slug is never copied since slugs are unique.
+	ArchiveSlug string
+}
+
+// Create a draft copy of an existing campaign. Params are optional and override the copied fields.
+func (c *Client) CloneCampaign(ctx context.Context, id int, params *CloneCampaignParams) (*Campaign, error) {
+	source, err := c.GetCampaign(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := campaignToParams(source)
+	clone.Name = fmt.Sprintf("Copy of %s", source.Name)
+
+	if params != nil {
+		if len(params.Name) > 0 {
+			clone.Name = params.Name
+		}
+		if len(params.Subject) > 0 {
+			clone.Subject = params.Subject
+		}
+		if params.Lists != nil {
+			clone.Lists = params.Lists
+		}
+		if !params.SendAt.IsZero() {
+			clone.SendAt = params.SendAt
+		}
+		if params.Tags != nil {
+			clone.Tags = params.Tags
+		}
+		clone.ArchiveSlug = params.ArchiveSlug
+	}
+
+	return c.CreateCampaign(ctx, clone)
+}
+
+// Maps the editable fields of a campaign onto creation params, leaving out its schedule and archive slug.
+func campaignToParams(campaign *Campaign) *CreateCampaignParams {
+	params := &CreateCampaignParams{
+		Name:              campaign.Name,
+		Subject:           campaign.Subject,
+		FromEmail:         campaign.FromEmail,
+		Type:              campaign.Type,
+		ContentType:       campaign.ContentType,
+		Body:              campaign.Body,
+		BodySource:        campaign.BodySource,
+		Altbody:           campaign.AltBody,
+		Messenger:         campaign.Messenger,
+		TemplateID:        campaign.TemplateID,
+		Tags:              campaign.Tags,
+		Headers:           campaign.Headers,
+		Archive:           campaign.Archive,
+		ArchiveTemplateID: campaign.ArchiveTemplateID,
+		ArchiveMeta:       campaign.ArchiveMeta,
+	}
+
+	for _, list := range campaign.Lists {
+		params.Lists = append(params.Lists, list.ID)
+	}
+	for _, media := range campaign.Media {
+		params.Media = append(params.Media, media.ID)
+	}
+
+	return params
+}
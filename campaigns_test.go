@@ -0,0 +1,91 @@
+package listmonkgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+func TestCloneCampaign(t *testing.T) {
+	var created map[string]any
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/campaigns/3", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {
+			"id": 3, "name": "Weekly", "subject": "Hello", "type": "regular", "content_type": "visual",
+			"body": "<p>Hi</p>", "body_source": "{\"blocks\":[]}", "altbody": "Hi", "template_id": 2,
+			"tags": ["weekly"], "headers": [{"X-Custom": "1"}], "archive": true, "archive_slug": "weekly",
+			"archive_template_id": 4, "media": [{"id": 7, "filename": "a.png"}],
+			"lists": [{"id": 1, "name": "One"}, {"id": 5, "name": "Five"}],
+			"send_at": "2020-01-01T00:00:00Z", "status": "finished"
+		}}`))
+	})
+	mux.HandleFunc("POST /api/campaigns", func(w http.ResponseWriter, r *http.Request) {
+		if len(r.URL.RawQuery) > 0 {
+			t.Errorf("unexpected query string %q", r.URL.RawQuery)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+			t.Error(err)
+			return
+		}
+		w.Write([]byte(`{"data": {"id": 4, "name": "Weekly #2", "status": "draft"}}`))
+	})
+	client := createTestClient(t, mux)
+
+	campaign, err := client.CloneCampaign(context.Background(), 3, &listmonkgo.CloneCampaignParams{Name: "Weekly #2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if campaign.ID != 4 {
+		t.Errorf("expected the created campaign, got %d", campaign.ID)
+	}
+
+	expected := map[string]any{
+		"name":                "Weekly #2",
+		"subject":             "Hello",
+		"content_type":        "visual",
+		"body_source":         `{"blocks":[]}`,
+		"altbody":             "Hi",
+		"template_id":         float64(2),
+		"archive":             true,
+		"archive_template_id": float64(4),
+	}
+	for key, value := range expected {
+		if created[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, created[key])
+		}
+	}
+	if lists, _ := json.Marshal(created["lists"]); string(lists) != "[1,5]" {
+		t.Errorf("expected lists [1,5], got %s", lists)
+	}
+	if media, _ := json.Marshal(created["media"]); string(media) != "[7]" {
+		t.Errorf("expected media [7], got %s", media)
+	}
+	for _, key := range []string{"send_at", "archive_slug"} {
+		if _, ok := created[key]; ok {
+			t.Errorf("expected %s not to be copied", key)
+		}
+	}
+}
+
+func TestPreviewCampaignContent(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/campaigns/9/preview", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("template_id") != "2" || r.FormValue("content_type") != "markdown" {
+			t.Errorf("unexpected form %v", r.Form)
+		}
+		w.Write([]byte("<html>" + r.FormValue("body") + "</html>"))
+	})
+	client := createTestClient(t, mux)
+
+	preview, err := client.PreviewCampaignContent(context.Background(), 9, 2, listmonkgo.MarkdownCampaignContent, "# Draft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview != "<html># Draft</html>" {
+		t.Errorf("unexpected preview %q", preview)
+	}
+}
@@ -0,0 +1,131 @@
+package campaignsync_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+	"github.com/canpacis/listmonk-go/campaignsync"
+)
+
+const document = `---
+subject: "Weekly news #42"
+lists: [Newsletter]
+tags: [weekly]
+template: Default
+---
+
+# Hello
+
+This week...
+`
+
+func TestParse(t *testing.T) {
+	doc, err := campaignsync.Parse("posts/weekly-42.md", strings.NewReader(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Slug != "weekly-42" || doc.Name != "Weekly news #42" || doc.Template != "Default" {
+		t.Errorf("unexpected front matter %+v", doc.FrontMatter)
+	}
+	if doc.Body != "# Hello\n\nThis week..." {
+		t.Errorf("unexpected body %q", doc.Body)
+	}
+
+	if _, err := campaignsync.Parse("empty.md", strings.NewReader("# No front matter")); err == nil {
+		t.Error("expected missing front matter to fail")
+	}
+}
+
+func TestSync(t *testing.T) {
+	created := []map[string]any{}
+	updated := map[string]map[string]any{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/lists", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"results": [{"id": 3, "name": "Newsletter"}], "total": 1}}`))
+	})
+	mux.HandleFunc("GET /api/templates", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{"id": 1, "name": "Default"}]}`))
+	})
+	mux.HandleFunc("GET /api/campaigns", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"results": [
+			{"id": 10, "name": "Old", "subject": "Old", "status": "draft", "tags": ["slug:weekly-41"], "content_type": "markdown", "body": "Old", "lists": [{"id": 3}], "archive": true, "archive_slug": "weekly-41", "altbody": "Old", "media": [{"id": 7}]},
+			{"id": 11, "name": "Done", "subject": "Done", "status": "finished", "tags": ["slug:weekly-40"], "content_type": "markdown", "body": "Done", "lists": [{"id": 3}]}
+		], "total": 2}}`))
+	})
+	mux.HandleFunc("POST /api/campaigns", func(w http.ResponseWriter, r *http.Request) {
+		var params map[string]any
+		json.NewDecoder(r.Body).Decode(&params)
+		created = append(created, params)
+		w.Write([]byte(`{"data": {"id": 12}}`))
+	})
+	mux.HandleFunc("PUT /api/campaigns/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var params map[string]any
+		json.NewDecoder(r.Body).Decode(&params)
+		updated[r.PathValue("id")] = params
+		w.Write([]byte(`{"data": {"id": 10}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	docs := []*campaignsync.Document{}
+	for _, content := range []string{
+		document,
+		"---\nslug: weekly-41\nsubject: New\nlists: [Newsletter]\n---\nNew",
+		"---\nslug: weekly-40\nsubject: Changed\nlists: [Newsletter]\n---\nDone",
+	} {
+		doc, err := campaignsync.Parse("doc.md", strings.NewReader(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, doc)
+	}
+	docs[0].Slug = "weekly-42"
+
+	syncer := campaignsync.New(listmonkgo.New(listmonkgo.WithBaseURL(server.URL)))
+	plan, err := syncer.Plan(context.Background(), docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actions := []campaignsync.Action{}
+	for _, change := range plan.Changes {
+		actions = append(actions, change.Action)
+	}
+	expected := []campaignsync.Action{campaignsync.CreateAction, campaignsync.UpdateAction, campaignsync.LockedAction}
+	if len(actions) != len(expected) {
+		t.Fatalf("expected actions %v, got %v", expected, actions)
+	}
+	for i := range expected {
+		if actions[i] != expected[i] {
+			t.Fatalf("expected actions %v, got %v", expected, actions)
+		}
+	}
+	if len(created) != 0 || len(updated) != 0 {
+		t.Fatal("planning must not change campaigns")
+	}
+
+	if err := syncer.Apply(context.Background(), plan); err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 1 || created[0]["subject"] != "Weekly news #42" || created[0]["template_id"] != float64(1) {
+		t.Errorf("unexpected created campaigns %v", created)
+	}
+	if tags, _ := json.Marshal(created[0]["tags"]); string(tags) != `["weekly","slug:weekly-42"]` {
+		t.Errorf("unexpected tags %s", tags)
+	}
+	if len(updated) != 1 || updated["10"]["body"] != "New" {
+		t.Errorf("unexpected updated campaigns %v", updated)
+	}
+	if updated["10"]["archive"] != true || updated["10"]["archive_slug"] != "weekly-41" || updated["10"]["altbody"] != "Old" {
+		t.Errorf("expected unmanaged fields to be kept, got %v", updated["10"])
+	}
+	if media, _ := json.Marshal(updated["10"]["media"]); string(media) != "[7]" {
+		t.Errorf("expected media [7], got %s", media)
+	}
+}
@@ -0,0 +1,117 @@
+// Package campaignsync keeps listmonk campaigns in sync with markdown files that carry YAML front matter.
+package campaignsync
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type FrontMatter struct {
+	// Stable identifier of the campaign. Defaults to the file name without its extension.
+	Slug string `yaml:"slug"`
+	// Campaign name. Defaults to the subject.
+	Name string `yaml:"name"`
+	// Campaign email subject.
+	Subject string `yaml:"subject"`
+	// Names of the lists to send campaign to.
+	Lists []string `yaml:"lists"`
+	// Tags to mark campaign.
+	Tags []string `yaml:"tags"`
+	// Name of the template to use. Defaults to the default template if not provided.
+	Template string `yaml:"template"`
+	// 'From' email in campaign emails. Defaults to value from settings if not provided.
+	FromEmail string `yaml:"from_email"`
+	// Timestamp to schedule campaign.
+	SendAt time.Time `yaml:"send_at"`
+}
+
+type Document struct {
+	FrontMatter
+	// Path of the file the document was read from, if any.
+	Path string
+	// Markdown body following the front matter.
+	Body string
+}
+
+const delimiter = "---\n"
+
+// Parse a markdown document with YAML front matter. The name is used to derive a slug when the
+// front matter does not set one.
+func Parse(name string, r io.Reader) (*Document, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+
+	if !strings.HasPrefix(text, delimiter) {
+		return nil, fmt.Errorf("%s: missing front matter", name)
+	}
+	matter, body, found := strings.Cut(text[len(delimiter):]+"\n", "\n"+delimiter)
+	if !found {
+		return nil, fmt.Errorf("%s: unterminated front matter", name)
+	}
+
+	doc := &Document{Path: name}
+	if err := yaml.Unmarshal([]byte(matter), &doc.FrontMatter); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	doc.Body = strings.TrimSpace(body)
+
+	if len(doc.Slug) == 0 {
+		base := filepath.Base(name)
+		doc.Slug = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	if len(doc.Name) == 0 {
+		doc.Name = doc.Subject
+	}
+	if len(doc.Subject) == 0 {
+		return nil, fmt.Errorf("%s: subject is required", name)
+	}
+	if len(doc.Lists) == 0 {
+		return nil, fmt.Errorf("%s: at least one list is required", name)
+	}
+	return doc, nil
+}
+
+// Parse every markdown file in a directory. Slugs must be unique across the directory.
+func ParseDir(dir string) ([]*Document, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+
+	docs := []*Document{}
+	slugs := map[string]string{}
+	for _, path := range paths {
+		doc, err := parseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := slugs[doc.Slug]; ok {
+			return nil, fmt.Errorf("%s: slug %q is already used by %s", path, doc.Slug, other)
+		}
+		slugs[doc.Slug] = path
+		docs = append(docs, doc)
+	}
+	if len(docs) == 0 {
+		return nil, errors.New("no markdown files found in " + dir)
+	}
+	return docs, nil
+}
+
+func parseFile(path string) (*Document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Parse(path, file)
+}
@@ -0,0 +1,305 @@
+package campaignsync
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+// Prefix of the campaign tag that stores a document's slug.
+const SlugTagPrefix = "slug:"
+
+// Page size used when listing campaigns, lists and templates.
+const pageSize = 100
+
+type Action string
+
+const (
+	CreateAction    Action = "create"
+	UpdateAction    Action = "update"
+	UnchangedAction Action = "unchanged"
+	// The campaign exists but can no longer be edited since it is running, finished or cancelled.
+	LockedAction Action = "locked"
+)
+
+type Change struct {
+	Action   Action
+	Document *Document
+	// ID of the existing campaign, zero when the campaign is created.
+	CampaignID int
+	// Fields of the campaign that differ from the document.
+	Fields []string
+	Params *listmonkgo.CreateCampaignParams
+}
+
+type Plan struct {
+	Changes []Change
+}
+
+// Human readable report of the plan, one line per document.
+func (p *Plan) String() string {
+	builder := new(strings.Builder)
+	for _, change := range p.Changes {
+		switch change.Action {
+		case CreateAction:
+			fmt.Fprintf(builder, "+ %s: create %q\n", change.Document.Slug, change.Params.Name)
+		case UpdateAction:
+			fmt.Fprintf(builder, "~ %s: update campaign %d (%s)\n", change.Document.Slug, change.CampaignID, strings.Join(change.Fields, ", "))
+		case UnchangedAction:
+			fmt.Fprintf(builder, "  %s: campaign %d is up to date\n", change.Document.Slug, change.CampaignID)
+		case LockedAction:
+			fmt.Fprintf(builder, "! %s: campaign %d can no longer be edited\n", change.Document.Slug, change.CampaignID)
+		}
+	}
+	return builder.String()
+}
+
+// Whether applying the plan would change anything.
+func (p *Plan) HasChanges() bool {
+	for _, change := range p.Changes {
+		if change.Action == CreateAction || change.Action == UpdateAction {
+			return true
+		}
+	}
+	return false
+}
+
+type Syncer struct {
+	client *listmonkgo.Client
+}
+
+func New(client *listmonkgo.Client) *Syncer {
+	return &Syncer{client: client}
+}
+
+// Compare the documents with the campaigns in listmonk without changing anything. Campaigns are matched
+// by their slug tag, or by their archive slug for campaigns that were not created by a sync.
+func (s *Syncer) Plan(ctx context.Context, docs []*Document) (*Plan, error) {
+	lists, err := s.lists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := s.templates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	campaigns, err := s.campaigns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{}
+	for _, doc := range docs {
+		params, err := toParams(doc, lists, templates)
+		if err != nil {
+			return nil, err
+		}
+
+		campaign := find(campaigns, doc.Slug)
+		if campaign == nil {
+			plan.Changes = append(plan.Changes, Change{Action: CreateAction, Document: doc, Params: params})
+			continue
+		}
+
+		params = overlay(campaign, params)
+		change := Change{Document: doc, CampaignID: campaign.ID, Params: params, Fields: diff(campaign, params)}
+		switch {
+		case len(change.Fields) == 0:
+			change.Action = UnchangedAction
+		case !editable(campaign):
+			change.Action = LockedAction
+		default:
+			change.Action = UpdateAction
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	return plan, nil
+}
+
+// Create and update the campaigns in the plan. Unchanged and locked campaigns are skipped.
+func (s *Syncer) Apply(ctx context.Context, plan *Plan) error {
+	for i, change := range plan.Changes {
+		switch change.Action {
+		case CreateAction:
+			campaign, err := s.client.CreateCampaign(ctx, change.Params)
+			if err != nil {
+				return fmt.Errorf("%s: %w", change.Document.Slug, err)
+			}
+			plan.Changes[i].CampaignID = campaign.ID
+		case UpdateAction:
+			if _, err := s.client.UpdateCampaign(ctx, change.CampaignID, change.Params); err != nil {
+				return fmt.Errorf("%s: %w", change.Document.Slug, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Syncer) lists(ctx context.Context) (map[string]int, error) {
+	ids := map[string]int{}
+	for page := 1; ; page++ {
+		resp, err := s.client.GetLists(ctx, &listmonkgo.GetListsParams{Page: page, PerPage: pageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, list := range resp.Results {
+			ids[list.Name] = list.ID
+		}
+		if len(resp.Results) == 0 || page*pageSize >= resp.Total {
+			return ids, nil
+		}
+	}
+}
+
+func (s *Syncer) templates(ctx context.Context) (map[string]int, error) {
+	templates, err := s.client.GetTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := map[string]int{}
+	for _, template := range templates {
+		ids[template.Name] = template.ID
+	}
+	return ids, nil
+}
+
+func (s *Syncer) campaigns(ctx context.Context) ([]listmonkgo.Campaign, error) {
+	campaigns := []listmonkgo.Campaign{}
+	for page := 1; ; page++ {
+		resp, err := s.client.GetCampaigns(ctx, &listmonkgo.GetCampaignParams{Page: page, PerPage: pageSize})
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, resp.Results...)
+		if len(resp.Results) == 0 || page*pageSize >= resp.Total {
+			return campaigns, nil
+		}
+	}
+}
+
+func toParams(doc *Document, lists, templates map[string]int) (*listmonkgo.CreateCampaignParams, error) {
+	params := &listmonkgo.CreateCampaignParams{
+		Name:        doc.Name,
+		Subject:     doc.Subject,
+		FromEmail:   doc.FromEmail,
+		Type:        listmonkgo.RegularCampaign,
+		ContentType: listmonkgo.MarkdownCampaignContent,
+		Body:        doc.Body,
+		SendAt:      doc.SendAt,
+		Tags:        append(slices.Clone(doc.Tags), SlugTagPrefix+doc.Slug),
+	}
+
+	for _, name := range doc.Lists {
+		id, ok := lists[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown list %q", doc.Slug, name)
+		}
+		params.Lists = append(params.Lists, id)
+	}
+
+	if len(doc.Template) > 0 {
+		id, ok := templates[doc.Template]
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown template %q", doc.Slug, doc.Template)
+		}
+		params.TemplateID = id
+	}
+	return params, nil
+}
+
+// Updates replace the whole campaign, so the fields the document does not manage are carried over from the
+// existing campaign.
+func overlay(campaign *listmonkgo.Campaign, params *listmonkgo.CreateCampaignParams) *listmonkgo.CreateCampaignParams {
+	merged := *params
+	merged.Altbody = campaign.AltBody
+	merged.Messenger = campaign.Messenger
+	merged.Headers = campaign.Headers
+	merged.Archive = campaign.Archive
+	merged.ArchiveSlug = campaign.ArchiveSlug
+	merged.ArchiveTemplateID = campaign.ArchiveTemplateID
+	merged.ArchiveMeta = campaign.ArchiveMeta
+	for _, media := range campaign.Media {
+		merged.Media = append(merged.Media, media.ID)
+	}
+	if len(campaign.Type) > 0 {
+		merged.Type = campaign.Type
+	}
+	if len(merged.FromEmail) == 0 {
+		merged.FromEmail = campaign.FromEmail
+	}
+	if merged.TemplateID == 0 {
+		merged.TemplateID = campaign.TemplateID
+	}
+	return &merged
+}
+
+func find(campaigns []listmonkgo.Campaign, slug string) *listmonkgo.Campaign {
+	for i := range campaigns {
+		if slices.Contains(campaigns[i].Tags, SlugTagPrefix+slug) {
+			return &campaigns[i]
+		}
+	}
+	for i := range campaigns {
+		if campaigns[i].ArchiveSlug == slug {
+			return &campaigns[i]
+		}
+	}
+	return nil
+}
+
+func editable(campaign *listmonkgo.Campaign) bool {
+	switch listmonkgo.CampaignStatus(campaign.Status) {
+	case listmonkgo.CampaignStatusDraft, listmonkgo.CampaignStatusScheduled, listmonkgo.CampaignStatusPaused:
+		return true
+	}
+	return false
+}
+
+func diff(campaign *listmonkgo.Campaign, params *listmonkgo.CreateCampaignParams) []string {
+	fields := []string{}
+	if campaign.Name != params.Name {
+		fields = append(fields, "name")
+	}
+	if campaign.Subject != params.Subject {
+		fields = append(fields, "subject")
+	}
+	if campaign.ContentType != params.ContentType || strings.TrimSpace(campaign.Body) != params.Body {
+		fields = append(fields, "body")
+	}
+	if len(params.FromEmail) > 0 && campaign.FromEmail != params.FromEmail {
+		fields = append(fields, "from_email")
+	}
+	if params.TemplateID != 0 && campaign.TemplateID != params.TemplateID {
+		fields = append(fields, "template")
+	}
+	if !campaign.SendAt.Equal(params.SendAt) {
+		fields = append(fields, "send_at")
+	}
+
+	lists := []int{}
+	for _, list := range campaign.Lists {
+		lists = append(lists, list.ID)
+	}
+	if !sameSet(lists, params.Lists) {
+		fields = append(fields, "lists")
+	}
+	if !sameSet(campaign.Tags, params.Tags) {
+		fields = append(fields, "tags")
+	}
+	return fields
+}
+
+func sameSet[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, item := range a {
+		if !slices.Contains(b, item) {
+			return false
+		}
+	}
+	return true
+}
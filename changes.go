@@ -0,0 +1,376 @@
+package listmonkgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Default page size of the change feed.
+const changesPageSize = 500
+
+type SubscriberEventType string
+
+const (
+	// The subscriber was not in the snapshot store.
+	SubscriberCreated SubscriberEventType = "created"
+	SubscriberUpdated SubscriberEventType = "updated"
+	// The status of the subscriber changed to blocklisted.
+	SubscriberBlocklisted SubscriberEventType = "blocklisted"
+	// The subscription to a list changed to unsubscribed.
+	SubscriberUnsubscribed SubscriberEventType = "unsubscribed"
+)
+
+type SubscriberEvent struct {
+	Type       SubscriberEventType
+	Subscriber Subscriber
+	// Snapshot of the subscriber before the change, nil for created events.
+	Previous *Subscriber
+	// List the subscriber unsubscribed from, only for unsubscribed events.
+	ListID int
+}
+
+// Position of the change feed in (updated_at, id) order. Subscribers updated at the watermark time with IDs
+// up to the watermark ID were already processed, so that ties at the same timestamp are neither dropped nor
+// repeated.
+type ChangeWatermark struct {
+	Time time.Time `json:"time"`
+	ID   int       `json:"id"`
+	// Latest subscription change that was processed. listmonk records changes of a subscription, such as an
+	// unsubscribe, on the subscription without updating the subscriber, so they are followed separately.
+	Subscriptions time.Time `json:"subscriptions"`
+}
+
+// Persists the snapshots of subscribers and the watermark of the change feed. Implementations must be safe
+// for concurrent use.
+type SubscriberStore interface {
+	// Saved watermark, nil if none was saved.
+	Watermark(ctx context.Context) (*ChangeWatermark, error)
+	// Snapshots of the subscribers with the given IDs. Unknown subscribers are left out.
+	Snapshots(ctx context.Context, ids []int) (map[int]*Subscriber, error)
+	// Save the snapshots of a batch of subscribers along with the new watermark.
+	Save(ctx context.Context, snapshots []Subscriber, watermark ChangeWatermark) error
+}
+
+// Keeps snapshots in memory. The feed starts over when the process exits.
+type MemorySubscriberStore struct {
+	mu        sync.Mutex
+	watermark *ChangeWatermark
+	snapshots map[int]Subscriber
+}
+
+func NewMemorySubscriberStore() *MemorySubscriberStore {
+	return &MemorySubscriberStore{snapshots: map[int]Subscriber{}}
+}
+
+func (s *MemorySubscriberStore) Watermark(ctx context.Context) (*ChangeWatermark, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watermark == nil {
+		return nil, nil
+	}
+	watermark := *s.watermark
+	return &watermark, nil
+}
+
+func (s *MemorySubscriberStore) Snapshots(ctx context.Context, ids []int) (map[int]*Subscriber, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshots := map[int]*Subscriber{}
+	for _, id := range ids {
+		if snapshot, ok := s.snapshots[id]; ok {
+			snapshots[id] = &snapshot
+		}
+	}
+	return snapshots, nil
+}
+
+func (s *MemorySubscriberStore) Save(ctx context.Context, snapshots []Subscriber, watermark ChangeWatermark) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, snapshot := range snapshots {
+		s.snapshots[snapshot.ID] = snapshot
+	}
+	s.watermark = &watermark
+	return nil
+}
+
+// Keeps snapshots and the watermark in a single JSON file that is rewritten atomically on every save, which
+// suits lists of moderate size.
+type FileSubscriberStore struct {
+	memory *MemorySubscriberStore
+	path   string
+}
+
+type subscriberStoreFile struct {
+	Watermark *ChangeWatermark `json:"watermark"`
+	Snapshots []Subscriber     `json:"snapshots"`
+}
+
+// Open the store at the path, creating it on the first save if it does not exist.
+func OpenFileSubscriberStore(path string) (*FileSubscriberStore, error) {
+	store := &FileSubscriberStore{memory: NewMemorySubscriberStore(), path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	file := subscriberStoreFile{}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	store.memory.watermark = file.Watermark
+	for _, snapshot := range file.Snapshots {
+		store.memory.snapshots[snapshot.ID] = snapshot
+	}
+	return store, nil
+}
+
+func (s *FileSubscriberStore) Watermark(ctx context.Context) (*ChangeWatermark, error) {
+	return s.memory.Watermark(ctx)
+}
+
+func (s *FileSubscriberStore) Snapshots(ctx context.Context, ids []int) (map[int]*Subscriber, error) {
+	return s.memory.Snapshots(ctx, ids)
+}
+
+func (s *FileSubscriberStore) Save(ctx context.Context, snapshots []Subscriber, watermark ChangeWatermark) error {
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+
+	merged := maps.Clone(s.memory.snapshots)
+	for _, snapshot := range snapshots {
+		merged[snapshot.ID] = snapshot
+	}
+	file := subscriberStoreFile{Watermark: &watermark}
+	for _, id := range slices.Sorted(maps.Keys(merged)) {
+		file.Snapshots = append(file.Snapshots, merged[id])
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+
+	s.memory.snapshots = merged
+	s.memory.watermark = &watermark
+	return nil
+}
+
+type SubscriberChangesOptions struct {
+	// Store of snapshots and the watermark. Defaults to a memory store.
+	Store SubscriberStore
+	// Subscribers per request. Defaults to 500.
+	PerPage int
+	// Called when a poll fails, eg: for logging. The poll is retried after the interval.
+	OnError func(err error)
+}
+
+// Poll subscribers updated after the saved watermark, or at or after since when none was saved, and emit
+// events by diffing them against their snapshots. Subscribers are read in (updated_at, id) order, followed by
+// the subscribers whose subscriptions changed, and the watermark is saved after the events of every batch
+// are delivered, so events are delivered at least once. Intervals that are not positive default to a minute.
+// The channel is closed when the context is cancelled. Options are optional.
+func (c *Client) SubscriberChanges(ctx context.Context, since time.Time, interval time.Duration, opts *SubscriberChangesOptions) <-chan SubscriberEvent {
+	if opts == nil {
+		opts = &SubscriberChangesOptions{}
+	}
+	store := opts.Store
+	if store == nil {
+		store = NewMemorySubscriberStore()
+	}
+	perPage := opts.PerPage
+	if perPage == 0 {
+		perPage = changesPageSize
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	events := make(chan SubscriberEvent)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			err := c.pollSubscriberChanges(ctx, store, since, perPage, events)
+			if err != nil && ctx.Err() == nil && opts.OnError != nil {
+				opts.OnError(err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return events
+}
+
+// Read batches of changed subscribers, then of subscribers with changed subscriptions, until the feed is
+// caught up.
+func (c *Client) pollSubscriberChanges(ctx context.Context, store SubscriberStore, since time.Time, perPage int, events chan<- SubscriberEvent) error {
+	saved, err := store.Watermark(ctx)
+	if err != nil {
+		return err
+	}
+	watermark := ChangeWatermark{Time: since}
+	if saved != nil {
+		watermark = *saved
+	}
+	// Read first, so that every subscription change up to it is seen by one of the scans below
+	latest, err := c.latestSubscriptionChange(ctx)
+	if err != nil {
+		return err
+	}
+
+	scan := subscriberScan{perPage: perPage}
+	for {
+		batch, err := c.scanSubscribers(ctx, &scan, watermark.Time, watermark.ID)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		last := batch[len(batch)-1]
+		watermark.Time, watermark.ID = last.UpdatedAt, last.ID
+		if err := emitSubscriberChanges(ctx, store, batch, watermark, events); err != nil {
+			return err
+		}
+	}
+
+	if !latest.After(watermark.Subscriptions) {
+		return nil
+	}
+	// Without a starting point the scan above read every subscriber after their subscriptions last changed
+	from := watermark.Subscriptions
+	if from.IsZero() {
+		from = since
+	}
+	afterID := 0
+	for !from.IsZero() {
+		condition := fmt.Sprintf("subscribers.id IN (SELECT subscriber_id FROM subscriber_lists WHERE updated_at > '%s' AND updated_at <= '%s') AND subscribers.id > %d",
+			formatCursor(from), formatCursor(latest), afterID)
+		batch, err := c.scanQuery(ctx, &scan, "id", condition)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		afterID = batch[len(batch)-1].ID
+		if err := emitSubscriberChanges(ctx, store, batch, watermark, events); err != nil {
+			return err
+		}
+	}
+	watermark.Subscriptions = latest
+	return store.Save(ctx, nil, watermark)
+}
+
+// Time of the latest subscription change, zero when there are no subscriptions.
+func (c *Client) latestSubscriptionChange(ctx context.Context) (time.Time, error) {
+	resp, err := c.GetSubscribers(ctx, &GetSubscribersParams{
+		Query:   "subscribers.id = (SELECT subscriber_id FROM subscriber_lists ORDER BY updated_at DESC LIMIT 1)",
+		Page:    1,
+		PerPage: 1,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	latest := time.Time{}
+	for _, subscriber := range resp.Results {
+		for _, list := range subscriber.Lists {
+			if list.SubscriptionUpdatedAt.After(latest) {
+				latest = list.SubscriptionUpdatedAt
+			}
+		}
+	}
+	return latest, nil
+}
+
+// Deliver the events of a batch of subscribers and save their snapshots along with the watermark.
+func emitSubscriberChanges(ctx context.Context, store SubscriberStore, batch []Subscriber, watermark ChangeWatermark, events chan<- SubscriberEvent) error {
+	ids := []int{}
+	for _, subscriber := range batch {
+		ids = append(ids, subscriber.ID)
+	}
+	snapshots, err := store.Snapshots(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, subscriber := range batch {
+		for _, event := range diffSubscriber(snapshots[subscriber.ID], subscriber) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return store.Save(ctx, batch, watermark)
+}
+
+// Events of the changes between a snapshot and the current state of a subscriber.
+func diffSubscriber(previous *Subscriber, current Subscriber) []SubscriberEvent {
+	if previous == nil {
+		return []SubscriberEvent{{Type: SubscriberCreated, Subscriber: current}}
+	}
+
+	events := []SubscriberEvent{}
+	if current.Status == BlocklistedSubscriberStatus && previous.Status != BlocklistedSubscriberStatus {
+		events = append(events, SubscriberEvent{Type: SubscriberBlocklisted, Subscriber: current, Previous: previous})
+	}
+	statuses := map[int]string{}
+	for _, list := range previous.Lists {
+		statuses[list.ID] = list.SubscriptionStatus
+	}
+	for _, list := range current.Lists {
+		if list.SubscriptionStatus == "unsubscribed" && statuses[list.ID] != "unsubscribed" {
+			events = append(events, SubscriberEvent{Type: SubscriberUnsubscribed, Subscriber: current, Previous: previous, ListID: list.ID})
+		}
+	}
+	if len(events) == 0 && !sameSubscriber(previous, &current) {
+		events = append(events, SubscriberEvent{Type: SubscriberUpdated, Subscriber: current, Previous: previous})
+	}
+	return events
+}
+
+// Snapshots are compared by their encoding since they may have gone through a JSON round trip.
+func sameSubscriber(a, b *Subscriber) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
@@ -0,0 +1,110 @@
+package listmonkgo_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+func collect(t *testing.T, events <-chan listmonkgo.SubscriberEvent, count int) []string {
+	t.Helper()
+	collected := []string{}
+	for len(collected) < count {
+		select {
+		case event := <-events:
+			entry := fmt.Sprintf("%s %d", event.Type, event.Subscriber.ID)
+			if event.ListID != 0 {
+				entry += fmt.Sprintf(" list %d", event.ListID)
+			}
+			collected = append(collected, entry)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after events %v", collected)
+		}
+	}
+	return collected
+}
+
+func TestSubscriberChanges(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	subscriber := func(id int, updated time.Duration, status listmonkgo.SubscriberStatus, list string) listmonkgo.Subscriber {
+		return listmonkgo.Subscriber{
+			ID:        id,
+			Email:     fmt.Sprintf("%d@example.com", id),
+			Status:    status,
+			Lists:     []listmonkgo.Subscription{{ID: 1, SubscriptionStatus: list, SubscriptionUpdatedAt: base.Add(updated)}},
+			UpdatedAt: base.Add(updated),
+		}
+	}
+
+	server := &subscribersServer{}
+	// Three subscribers share a timestamp across a page boundary
+	server.set(
+		subscriber(1, time.Second, "enabled", "confirmed"),
+		subscriber(2, 2*time.Second, "enabled", "confirmed"),
+		subscriber(3, 2*time.Second, "enabled", "confirmed"),
+		subscriber(4, 2*time.Second, "enabled", "confirmed"),
+	)
+	client := createTestClient(t, server)
+	path := filepath.Join(t.TempDir(), "subscribers.json")
+	store, err := listmonkgo.OpenFileSubscriberStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := client.SubscriberChanges(ctx, base, 10*time.Millisecond, &listmonkgo.SubscriberChangesOptions{
+		Store:   store,
+		PerPage: 2,
+		OnError: func(err error) { t.Error(err) },
+	})
+	got := collect(t, events, 4)
+	if strings.Join(got, ", ") != "created 1, created 2, created 3, created 4" {
+		t.Errorf("unexpected events %v", got)
+	}
+
+	// listmonk only updates the subscription when a subscriber unsubscribes
+	unsubscribed := subscriber(3, 2*time.Second, "enabled", "unsubscribed")
+	unsubscribed.Lists[0].SubscriptionUpdatedAt = base.Add(3 * time.Second)
+	server.set(
+		subscriber(2, 3*time.Second, "blocklisted", "confirmed"),
+		unsubscribed,
+		listmonkgo.Subscriber{ID: 4, Email: "new@example.com", Status: "enabled", Lists: []listmonkgo.Subscription{{ID: 1, SubscriptionStatus: "confirmed"}}, UpdatedAt: base.Add(4 * time.Second)},
+	)
+	got = collect(t, events, 3)
+	if strings.Join(got, ", ") != "blocklisted 2, updated 4, unsubscribed 3 list 1" {
+		t.Errorf("unexpected events %v", got)
+	}
+	cancel()
+	for range events {
+	}
+
+	// A new feed resumes from the persisted watermark and snapshots
+	reopened, err := listmonkgo.OpenFileSubscriberStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watermark, err := reopened.Watermark(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if watermark == nil || !watermark.Time.Equal(base.Add(4*time.Second)) || watermark.ID != 4 {
+		t.Fatalf("unexpected watermark %+v", watermark)
+	}
+
+	resubscribed := subscriber(2, 3*time.Second, "blocklisted", "confirmed")
+	resubscribed.Lists = append(resubscribed.Lists, listmonkgo.Subscription{ID: 2, SubscriptionStatus: "unsubscribed", SubscriptionUpdatedAt: base.Add(6 * time.Second)})
+	server.set(subscriber(5, 4*time.Second, "enabled", "confirmed"), subscriber(1, 5*time.Second, "enabled", "confirmed"), resubscribed)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	// The first poll runs right away, so the default interval does not slow the test down
+	events = client.SubscriberChanges(ctx, base, 0, &listmonkgo.SubscriberChangesOptions{Store: reopened, PerPage: 2})
+	got = collect(t, events, 3)
+	if strings.Join(got, ", ") != "created 5, updated 1, unsubscribed 2 list 2" {
+		t.Errorf("unexpected events %v", got)
+	}
+}
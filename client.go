@@ -7,9 +7,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
+	"path/filepath"
+	"strings"
 
 	"github.com/google/go-querystring/query"
 )
@@ -28,7 +32,10 @@ func (c *Client) do(ctx context.Context, method, path string, data any) (*http.R
 		return nil, err
 	}
 
-	if data != nil {
+	hasBody := method == "POST" || method == "PUT"
+
+	// Request bodies are JSON encoded, only bodiless requests carry their data in the query string
+	if data != nil && !hasBody {
 		q, err := query.Values(data)
 		if err != nil {
 			return nil, err
@@ -41,7 +48,7 @@ func (c *Client) do(ctx context.Context, method, path string, data any) (*http.R
 
 	// This has to be io.Reader otherwise http.NewRequest call panics with nil values
 	var body io.Reader
-	if (method == "POST" || method == "PUT") && data != nil {
+	if hasBody && data != nil {
 		body = new(bytes.Buffer)
 		encoder := json.NewEncoder(body.(*bytes.Buffer))
 		if err := encoder.Encode(data); err != nil {
@@ -64,7 +71,20 @@ func (c *Client) do(ctx context.Context, method, path string, data any) (*http.R
 	return resp, nil
 }
 
-func (c *Client) multipart(ctx context.Context, path string, fields map[string]string, files map[string]io.Reader) (*http.Response, error) {
+// File part of a multipart request.
+type formFile struct {
+	// Name of the form field.
+	Field string
+	// File name sent to the server.
+	Filename string
+	// Content type of the part. Detected from the file name if empty.
+	ContentType string
+	Reader      io.Reader
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func (c *Client) multipart(ctx context.Context, path string, fields map[string]string, files []formFile) (*http.Response, error) {
 	endpoint, err := url.JoinPath(c.config.BaseURL, path)
 	if err != nil {
 		return nil, err
@@ -73,12 +93,23 @@ func (c *Client) multipart(ctx context.Context, path string, fields map[string]s
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
 
-	for key, r := range files {
-		w, err := writer.CreateFormFile(key, key)
+	for _, file := range files {
+		contentType := file.ContentType
+		if len(contentType) == 0 {
+			contentType = mime.TypeByExtension(filepath.Ext(file.Filename))
+		}
+		if len(contentType) == 0 {
+			contentType = "application/octet-stream"
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(file.Field), quoteEscaper.Replace(file.Filename)))
+		header.Set("Content-Type", contentType)
+		w, err := writer.CreatePart(header)
 		if err != nil {
 			return nil, err
 		}
-		if _, err := io.Copy(w, r); err != nil {
+		if _, err := io.Copy(w, file.Reader); err != nil {
 			return nil, err
 		}
 	}
@@ -134,6 +165,24 @@ func decode[T any](resp *http.Response) (*T, error) {
 	return data, nil
 }
 
+// Reads a non JSON response body, such as rendered HTML previews.
+func decodeText(resp *http.Response) (string, error) {
+	if resp.StatusCode != http.StatusOK {
+		decoder := json.NewDecoder(resp.Body)
+		data := new(ErrorResponse)
+		if err := decoder.Decode(data); err != nil {
+			return "", err
+		}
+		return "", errors.New(data.Message)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
 func request[T any](client *Client, ctx context.Context, method, path string, data any) (*T, error) {
 	resp, err := client.do(ctx, method, path, data)
 	if err != nil {
@@ -147,6 +196,8 @@ type ClientConfig struct {
 	APIUser    string
 	Token      string
 	HTTPClient *http.Client
+	// Lint template bodies before they are created or updated and reject the ones with errors.
+	LintTemplates bool
 }
 
 func WithBaseURL(baseUrl string) func(*ClientConfig) {
@@ -173,6 +224,12 @@ func WithHTTPClient(client *http.Client) func(*ClientConfig) {
 	}
 }
 
+func WithTemplateLinting(enabled bool) func(*ClientConfig) {
+	return func(cc *ClientConfig) {
+		cc.LintTemplates = enabled
+	}
+}
+
 type ConfigOption func(*ClientConfig)
 
 func New(options ...ConfigOption) *Client {
@@ -1,9 +1,12 @@
 package listmonkgo_test
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	listmonkgo "github.com/canpacis/listmonk-go"
 	"github.com/joho/godotenv"
 )
 
@@ -20,3 +23,21 @@ func TestMain(m *testing.M) {
 // 		listmonkgo.WithToken(os.Getenv("API_TOKEN")),
 // 	)
 // }
+
+// Starts an in-process server backed by the given handler and returns its URL.
+func createTestServerURL(t *testing.T, handler http.Handler) string {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// Creates a client that talks to an in-process server backed by the given handler.
+func createTestClient(t *testing.T, handler http.Handler) *listmonkgo.Client {
+	t.Helper()
+	return listmonkgo.New(
+		listmonkgo.WithBaseURL(createTestServerURL(t, handler)),
+		listmonkgo.WithAPIUser("test"),
+		listmonkgo.WithToken("token"),
+	)
+}
@@ -0,0 +1,92 @@
+package listmonkgo
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	// Campaign bodies routinely embed raw HTML, so it is passed through as listmonk does
+	goldmark.WithRendererOptions(gmhtml.WithUnsafe()),
+)
+
+// Convert a markdown body to HTML offline.
+func MarkdownToHTML(body string) (string, error) {
+	out := new(bytes.Buffer)
+	if err := markdown.Convert([]byte(body), out); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+var (
+	htmlInvisible  = regexp.MustCompile(`(?is)<(script|style|head|title)\b.*?</(script|style|head|title)\s*>|<!--.*?-->`)
+	htmlWhitespace = regexp.MustCompile(`\s+`)
+	htmlLink       = regexp.MustCompile(`(?is)<a\b[^>]*?\bhref\s*=\s*["']([^"']*)["'][^>]*>(.*?)</a\s*>`)
+	htmlLineBreak  = regexp.MustCompile(`(?i)<br\s*/?>`)
+	htmlListItem   = regexp.MustCompile(`(?i)<li\b[^>]*>`)
+	htmlBlock      = regexp.MustCompile(`(?i)</?(p|div|h[1-6]|ul|ol|table|tr|blockquote|pre|hr|section|article|header|footer)\b[^>]*>`)
+	htmlTag        = regexp.MustCompile(`(?s)<[^>]*>`)
+	plainSpaces    = regexp.MustCompile(`[ \t]+`)
+	plainNewlines  = regexp.MustCompile(`\n{3,}`)
+)
+
+// Convert an HTML body to plain text offline, suitable for use as a campaign's alternate body.
+// Links are kept as "text (url)" and block elements are separated by blank lines.
+func HTMLToPlain(body string) string {
+	body = htmlInvisible.ReplaceAllString(body, "")
+	body = htmlWhitespace.ReplaceAllString(body, " ")
+	body = htmlLink.ReplaceAllStringFunc(body, func(match string) string {
+		parts := htmlLink.FindStringSubmatch(match)
+		href := html.UnescapeString(parts[1])
+		text := strings.TrimSpace(htmlTag.ReplaceAllString(parts[2], ""))
+		if len(href) == 0 || strings.HasPrefix(href, "#") || html.UnescapeString(text) == href {
+			return text
+		}
+		if len(text) == 0 {
+			return href
+		}
+		return fmt.Sprintf("%s (%s)", text, href)
+	})
+	body = htmlLineBreak.ReplaceAllString(body, "\n")
+	body = htmlListItem.ReplaceAllString(body, "\n- ")
+	body = htmlBlock.ReplaceAllString(body, "\n\n")
+	body = htmlTag.ReplaceAllString(body, "")
+	body = html.UnescapeString(body)
+
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(plainSpaces.ReplaceAllString(line, " "))
+	}
+	body = strings.Join(lines, "\n")
+	body = plainNewlines.ReplaceAllString(body, "\n\n")
+	return strings.TrimSpace(body)
+}
+
+// Fill the alternate plain text body from the campaign body if it is not already set.
+// Only html, richtext and markdown campaigns are converted, other content types are left untouched.
+func (p *CreateCampaignParams) GenerateAltbody() error {
+	if len(p.Altbody) > 0 {
+		return nil
+	}
+
+	switch p.ContentType {
+	case HTMLCampaignContent, RichtextCampaignContent:
+		p.Altbody = HTMLToPlain(p.Body)
+	case MarkdownCampaignContent:
+		body, err := MarkdownToHTML(p.Body)
+		if err != nil {
+			return err
+		}
+		p.Altbody = HTMLToPlain(body)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package listmonkgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+func TestHTMLToPlain(t *testing.T) {
+	body := `<html><head><title>Hi</title><style>p { color: red; }</style></head>
+<body>
+  <h1>Weekly   news</h1>
+  <p>Read <a href="https://example.com/a?x=1&amp;y=2">the post</a>
+  or visit <a href="https://example.com">https://example.com</a>.</p>
+  <ul><li>One</li><li>Two &amp; three</li></ul>
+  <p>Bye<br>Team</p>
+</body></html>`
+
+	expected := "Weekly news\n\nRead the post (https://example.com/a?x=1&y=2) or visit https://example.com.\n\n- One\n- Two & three\n\nBye\nTeam"
+	if plain := listmonkgo.HTMLToPlain(body); plain != expected {
+		t.Errorf("unexpected plain text:\n%s", plain)
+	}
+}
+
+func TestGenerateAltbody(t *testing.T) {
+	params := &listmonkgo.CreateCampaignParams{
+		ContentType: listmonkgo.MarkdownCampaignContent,
+		Body:        "# Title\n\nSome *text* with a [link](https://example.com).",
+	}
+	if err := params.GenerateAltbody(); err != nil {
+		t.Fatal(err)
+	}
+	if params.Altbody != "Title\n\nSome text with a link (https://example.com)." {
+		t.Errorf("unexpected altbody:\n%s", params.Altbody)
+	}
+
+	params = &listmonkgo.CreateCampaignParams{ContentType: listmonkgo.HTMLCampaignContent, Body: "<p>New</p>", Altbody: "Kept"}
+	if err := params.GenerateAltbody(); err != nil {
+		t.Fatal(err)
+	}
+	if params.Altbody != "Kept" {
+		t.Errorf("expected existing altbody to be kept, got %q", params.Altbody)
+	}
+}
+
+func TestConvertCampaignContent(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/campaigns/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"id": 1, "content_type": "markdown", "body": "# Hi"}}`))
+	})
+	mux.HandleFunc("POST /api/campaigns/1/content", func(w http.ResponseWriter, r *http.Request) {
+		var params map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Error(err)
+			return
+		}
+		if params["from"] != "markdown" || params["to"] != "html" || params["body"] != "# Hi" {
+			t.Errorf("unexpected params %v", params)
+		}
+		w.Write([]byte(`{"data": "<h1>Hi</h1>"}`))
+	})
+	client := createTestClient(t, mux)
+
+	body, err := client.ConvertCampaignContent(context.Background(), 1, listmonkgo.MarkdownCampaignContent, listmonkgo.HTMLCampaignContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, "<h1>Hi</h1>") {
+		t.Errorf("unexpected body %q", body)
+	}
+}
@@ -0,0 +1,131 @@
+package listmonkgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Returned when a subscriber lookup matches no subscriber.
+var ErrSubscriberNotFound = errors.New("subscriber not found")
+
+// Subscriber to look up, built with SubscriberByID or SubscriberByEmail.
+type SubscriberLookup struct {
+	id    int
+	email string
+}
+
+// Look up a subscriber by ID.
+func SubscriberByID(id int) SubscriberLookup {
+	return SubscriberLookup{id: id}
+}
+
+// Look up a subscriber by email.
+func SubscriberByEmail(email string) SubscriberLookup {
+	return SubscriberLookup{email: email}
+}
+
+// Find the subscriber of a lookup.
+func (c *Client) LookupSubscriber(ctx context.Context, lookup SubscriberLookup) (*Subscriber, error) {
+	if lookup.id != 0 {
+		return c.GetSubscriber(ctx, lookup.id)
+	}
+	if len(lookup.email) == 0 {
+		return nil, errors.New("either an id or an email is required")
+	}
+
+	email := strings.ReplaceAll(strings.TrimSpace(lookup.email), "'", "''")
+	resp, err := c.GetSubscribers(ctx, &GetSubscribersParams{
+		Query:   fmt.Sprintf("LOWER(subscribers.email) = LOWER('%s')", email),
+		Page:    1,
+		PerPage: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Results) == 0 {
+		return nil, ErrSubscriberNotFound
+	}
+	return &resp.Results[0], nil
+}
+
+type EraseOptions struct {
+	// Optional writer to capture an export of the subscriber's data to before it is erased.
+	Export io.Writer
+	// Format of the export. Defaults to JSON.
+	ExportFormat ExportFormat
+	// Optional suppression list to add the subscriber's hashed email to, so that re-imports can skip them.
+	Suppression *SuppressionList
+}
+
+// Record of an erasure. The email hash is unsalted and can be reversed by guessing addresses, so it is
+// pseudonymous personal data and receipts must be stored accordingly.
+type ErasureReceipt struct {
+	SubscriberID   int       `json:"subscriber_id"`
+	SubscriberUUID uuid.UUID `json:"subscriber_uuid"`
+	// Hash of the email address, see HashEmail.
+	EmailHash string `json:"email_hash"`
+	// Format of the export captured before the erasure, empty when none was captured.
+	ExportFormat   ExportFormat `json:"export_format,omitempty"`
+	BouncesDeleted bool         `json:"bounces_deleted"`
+	Suppressed     bool         `json:"suppressed"`
+	ErasedAt       time.Time    `json:"erased_at"`
+}
+
+// Erase a subscriber for a right to be forgotten request: optionally export their data and suppress their
+// email address, then delete their bounce records and the subscriber. Suppression comes first so that a
+// subscriber is never erased without being suppressed. Options are optional. On failure the receipt records
+// the steps that were completed.
+func (c *Client) EraseSubscriber(ctx context.Context, lookup SubscriberLookup, opts *EraseOptions) (*ErasureReceipt, error) {
+	if opts == nil {
+		opts = &EraseOptions{}
+	}
+
+	subscriber, err := c.LookupSubscriber(ctx, lookup)
+	if err != nil {
+		return nil, err
+	}
+	receipt := &ErasureReceipt{
+		SubscriberID:   subscriber.ID,
+		SubscriberUUID: subscriber.UUID,
+		EmailHash:      HashEmail(subscriber.Email),
+	}
+
+	if opts.Export != nil {
+		format := opts.ExportFormat
+		if len(format) == 0 {
+			format = ExportFormatJSON
+		}
+		if err := c.ExportSubscriberTo(ctx, subscriber.ID, opts.Export, format); err != nil {
+			return receipt, fmt.Errorf("exporting subscriber: %w", err)
+		}
+		receipt.ExportFormat = format
+	}
+
+	if opts.Suppression != nil {
+		if err := opts.Suppression.Add(subscriber.Email); err != nil {
+			return receipt, fmt.Errorf("suppressing email: %w", err)
+		}
+		receipt.Suppressed = true
+	}
+
+	if _, err := c.DeleteSubscriberBounces(ctx, subscriber.ID); err != nil {
+		return receipt, fmt.Errorf("deleting bounces: %w", err)
+	}
+	receipt.BouncesDeleted = true
+
+	ok, err := c.DeleteSubscriber(ctx, subscriber.ID)
+	if err == nil && !ok {
+		err = errors.New("listmonk did not delete the subscriber")
+	}
+	if err != nil {
+		return receipt, fmt.Errorf("deleting subscriber: %w", err)
+	}
+	receipt.ErasedAt = time.Now().UTC()
+	return receipt, nil
+}
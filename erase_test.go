@@ -0,0 +1,91 @@
+package listmonkgo_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+func TestEraseSubscriber(t *testing.T) {
+	calls := []string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/subscribers", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "GET "+r.URL.Query().Get("query"))
+		if strings.Contains(r.URL.Query().Get("query"), "missing") {
+			w.Write([]byte(`{"data": {"results": [], "total": 0}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"results": [{"id": 4, "uuid": "0b0e5d2e-3f53-4b35-8a3c-6f1b1d2c3e4f", "email": "o'brien@example.com"}], "total": 1}}`))
+	})
+	mux.HandleFunc("GET /api/subscribers/{id}/export", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "export "+r.PathValue("id"))
+		w.Write([]byte(exportJSON))
+	})
+	mux.HandleFunc("DELETE /api/subscribers/{id}/bounces", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "delete bounces "+r.PathValue("id"))
+		w.Write([]byte(`{"data": true}`))
+	})
+	mux.HandleFunc("DELETE /api/subscribers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "delete "+r.PathValue("id"))
+		w.Write([]byte(`{"data": true}`))
+	})
+	client := createTestClient(t, mux)
+
+	path := filepath.Join(t.TempDir(), "suppressed.txt")
+	suppression, err := listmonkgo.OpenSuppressionList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	export := new(bytes.Buffer)
+	receipt, err := client.EraseSubscriber(context.Background(), listmonkgo.SubscriberByEmail(" O'Brien@example.com"), &listmonkgo.EraseOptions{Export: export, Suppression: suppression})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedCalls := []string{
+		"GET LOWER(subscribers.email) = LOWER('O''Brien@example.com')",
+		"export 4",
+		"delete bounces 4",
+		"delete 4",
+	}
+	if strings.Join(calls, "\n") != strings.Join(expectedCalls, "\n") {
+		t.Errorf("unexpected calls:\n%s", strings.Join(calls, "\n"))
+	}
+	if receipt.SubscriberID != 4 || receipt.EmailHash != listmonkgo.HashEmail("o'brien@example.com") || receipt.ExportFormat != listmonkgo.ExportFormatJSON ||
+		!receipt.BouncesDeleted || !receipt.Suppressed || receipt.ErasedAt.IsZero() {
+		t.Errorf("unexpected receipt %+v", receipt)
+	}
+	if !strings.Contains(export.String(), `"email": "a@example.com"`) {
+		t.Errorf("expected the export to be captured, got %s", export)
+	}
+
+	reopened, err := listmonkgo.OpenSuppressionList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reopened.Contains("O'BRIEN@example.com ") || reopened.Contains("other@example.com") {
+		t.Error("expected the erased email to be suppressed")
+	}
+
+	// A suppression list in a missing directory cannot be written to
+	broken, err := listmonkgo.OpenSuppressionList(filepath.Join(t.TempDir(), "missing", "suppressed.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls = nil
+	receipt, err = client.EraseSubscriber(context.Background(), listmonkgo.SubscriberByEmail("o'brien@example.com"), &listmonkgo.EraseOptions{Suppression: broken})
+	if err == nil || receipt.Suppressed || receipt.BouncesDeleted || slices.ContainsFunc(calls, func(call string) bool { return strings.HasPrefix(call, "delete") }) {
+		t.Errorf("expected nothing to be deleted when suppression fails, got %v and calls %v", err, calls)
+	}
+
+	if _, err := client.EraseSubscriber(context.Background(), listmonkgo.SubscriberByEmail("missing@example.com"), nil); !errors.Is(err, listmonkgo.ErrSubscriberNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
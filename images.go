@@ -0,0 +1,147 @@
+package listmonkgo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	htmlImage     = `(?i:<img\b[^>]*?\bsrc\s*=\s*(?:"([^"]*)"|'([^']*)'))`
+	markdownImage = `!\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+(?:"[^"]*"|'[^']*'))?\s*\)`
+)
+
+var (
+	htmlImagePattern = regexp.MustCompile(htmlImage)
+	// Markdown bodies can also contain inline HTML
+	markdownImagePattern = regexp.MustCompile(htmlImage + "|" + markdownImage)
+)
+
+type UploadCampaignImagesOptions struct {
+	// Directory that relative image paths are resolved against. Defaults to the working directory.
+	Dir string
+	// Optional validation of uploaded images.
+	Upload *UploadMediaOptions
+}
+
+// Upload the local images referenced by the body of a campaign, rewrite the references to the URLs of the
+// uploaded media and attach the media to the campaign. Images that are already in the media library with the
+// same filename and contents are reused instead of uploaded again. Call before CreateCampaign or
+// UpdateCampaign. Options are optional.
+func (c *Client) UploadCampaignImages(ctx context.Context, params *CreateCampaignParams, opts *UploadCampaignImagesOptions) error {
+	if opts == nil {
+		opts = &UploadCampaignImagesOptions{}
+	}
+
+	pattern := htmlImagePattern
+	if params.ContentType == MarkdownCampaignContent {
+		pattern = markdownImagePattern
+	}
+
+	paths := []string{}
+	replaceImages(params.Body, pattern, func(ref string) string {
+		if path, ok := localImage(ref, opts.Dir); ok && !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+		return ref
+	})
+	if len(paths) == 0 {
+		return nil
+	}
+
+	list, err := c.GetMediaList(ctx)
+	if err != nil {
+		return err
+	}
+	existing := map[string][]int{}
+	for _, media := range list {
+		existing[media.Filename] = append(existing[media.Filename], media.ID)
+	}
+
+	uploaded := map[string]*Media{}
+	for _, path := range paths {
+		media, err := c.uploadImage(ctx, path, existing[filepath.Base(path)], opts.Upload)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		uploaded[path] = media
+		if !slices.Contains(params.Media, media.ID) {
+			params.Media = append(params.Media, media.ID)
+		}
+	}
+
+	params.Body = replaceImages(params.Body, pattern, func(ref string) string {
+		if path, ok := localImage(ref, opts.Dir); ok {
+			return uploaded[path].URL
+		}
+		return ref
+	})
+	return nil
+}
+
+// Reuse a media file with the same contents as the image or upload it.
+func (c *Client) uploadImage(ctx context.Context, path string, candidates []int, opts *UploadMediaOptions) (*Media, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(data)
+
+	for _, id := range candidates {
+		media, err := c.GetMedia(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		remote := sha256.New()
+		if _, err := c.download(ctx, media, remote); err != nil {
+			return nil, err
+		}
+		if bytes.Equal(remote.Sum(nil), hash[:]) {
+			return media, nil
+		}
+	}
+	return c.UploadMedia(ctx, filepath.Base(path), bytes.NewReader(data), opts)
+}
+
+// Resolve an image reference to a local path. References with a scheme other than file, protocol relative
+// references and template expressions are not local.
+func localImage(ref, dir string) (string, bool) {
+	if len(ref) == 0 || strings.HasPrefix(ref, "//") || strings.Contains(ref, "{{") {
+		return "", false
+	}
+	parsed, err := url.Parse(ref)
+	if err != nil || (len(parsed.Scheme) > 0 && parsed.Scheme != "file") || len(parsed.Path) == 0 {
+		return "", false
+	}
+	if filepath.IsAbs(parsed.Path) || parsed.Scheme == "file" {
+		return filepath.Clean(parsed.Path), true
+	}
+	return filepath.Join(dir, parsed.Path), true
+}
+
+// Replace the image references matched by the pattern, leaving the rest of the body untouched.
+func replaceImages(body string, pattern *regexp.Regexp, replace func(ref string) string) string {
+	builder := new(strings.Builder)
+	last := 0
+	for _, match := range pattern.FindAllStringSubmatchIndex(body, -1) {
+		// The reference is the first group that matched
+		for group := 2; group < len(match); group += 2 {
+			if match[group] < 0 {
+				continue
+			}
+			builder.WriteString(body[last:match[group]])
+			builder.WriteString(replace(body[match[group]:match[group+1]]))
+			last = match[group+1]
+			break
+		}
+	}
+	builder.WriteString(body[last:])
+	return builder.String()
+}
@@ -0,0 +1,85 @@
+package listmonkgo_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+func TestUploadCampaignImages(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"logo.png": "logo", "chart.png": "chart", "photo.jpg": "photo"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	uploads := []string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/media", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{"id": 1, "filename": "logo.png"}, {"id": 2, "filename": "chart.png"}]}`))
+	})
+	mux.HandleFunc("GET /api/media/{id}", func(w http.ResponseWriter, r *http.Request) {
+		name := map[string]string{"1": "logo.png", "2": "chart.png"}[r.PathValue("id")]
+		fmt.Fprintf(w, `{"data": {"id": %s, "filename": %q, "url": "/uploads/%s"}}`, r.PathValue("id"), name, name)
+	})
+	mux.HandleFunc("GET /uploads/{name}", func(w http.ResponseWriter, r *http.Request) {
+		// The remote chart differs from the local one
+		w.Write([]byte(map[string]string{"logo.png": "logo", "chart.png": "old chart"}[r.PathValue("name")]))
+	})
+	mux.HandleFunc("POST /api/media", func(w http.ResponseWriter, r *http.Request) {
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		uploads = append(uploads, header.Filename)
+		id := 10 + len(uploads)
+		fmt.Fprintf(w, `{"data": {"id": %d, "filename": %q, "url": "http://cdn.example.com/%d/%s"}}`, id, header.Filename, id, header.Filename)
+	})
+	client := createTestClient(t, mux)
+
+	params := &listmonkgo.CreateCampaignParams{
+		ContentType: listmonkgo.MarkdownCampaignContent,
+		Body: "# News\n\n![Logo](logo.png \"Our logo\")\n![Chart](./chart.png)\n![Again](logo.png)\n" +
+			"<img class=\"hero\" src='photo.jpg'>\n![Remote](https://example.com/a.png)\n![Dynamic]({{ .Subscriber.Attribs.avatar }})\n",
+		Media: []int{5},
+	}
+	if err := client.UploadCampaignImages(context.Background(), params, &listmonkgo.UploadCampaignImagesOptions{Dir: dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "# News\n\n![Logo](/uploads/logo.png \"Our logo\")\n![Chart](http://cdn.example.com/11/chart.png)\n![Again](/uploads/logo.png)\n" +
+		"<img class=\"hero\" src='http://cdn.example.com/12/photo.jpg'>\n![Remote](https://example.com/a.png)\n![Dynamic]({{ .Subscriber.Attribs.avatar }})\n"
+	if params.Body != expected {
+		t.Errorf("unexpected body:\n%s", params.Body)
+	}
+	if fmt.Sprint(uploads) != "[chart.png photo.jpg]" {
+		t.Errorf("unexpected uploads %v", uploads)
+	}
+	if fmt.Sprint(params.Media) != "[5 1 11 12]" {
+		t.Errorf("unexpected media %v", params.Media)
+	}
+}
+
+func TestUploadCampaignImagesMissingFile(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/media", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"data": []}`)
+	})
+	client := createTestClient(t, mux)
+
+	params := &listmonkgo.CreateCampaignParams{ContentType: listmonkgo.HTMLCampaignContent, Body: `<img src="missing.png">`}
+	if err := client.UploadCampaignImages(context.Background(), params, &listmonkgo.UploadCampaignImagesOptions{Dir: t.TempDir()}); err == nil {
+		t.Error("expected an error for a missing image")
+	}
+	if params.Body != `<img src="missing.png">` {
+		t.Errorf("expected the body to be untouched, got %s", params.Body)
+	}
+}
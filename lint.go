@@ -0,0 +1,218 @@
+package listmonkgo
+
+import (
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// Bodies larger than this are clipped by Gmail.
+const MaxTemplateSize = 102 * 1024
+
+type LintSeverity string
+
+const (
+	// The template cannot be used as is and is rejected by pre-flight checks.
+	LintError LintSeverity = "error"
+	// The template works but is likely to cause problems once sent.
+	LintWarning LintSeverity = "warning"
+)
+
+type LintIssue struct {
+	Severity LintSeverity
+	// Line of the issue in the body, zero if it applies to the whole body.
+	Line    int
+	Message string
+}
+
+func (i LintIssue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("%s: line %d: %s", i.Severity, i.Line, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+}
+
+// Returned by CreateTemplate and UpdateTemplate when template linting is enabled and the body has errors.
+type TemplateLintError struct {
+	Issues []LintIssue
+}
+
+func (e *TemplateLintError) Error() string {
+	messages := []string{}
+	for _, issue := range e.Issues {
+		messages = append(messages, issue.String())
+	}
+	return "template failed linting: " + strings.Join(messages, "; ")
+}
+
+var (
+	lintContent     = regexp.MustCompile(`{{-?\s*template\s+"content"\s+\.\s*-?}}`)
+	lintUnsubscribe = regexp.MustCompile(`{{-?[^}]*\bUnsubscribeURL\b`)
+	lintUnknownFunc = regexp.MustCompile(`function "([^"]+)" not defined`)
+	lintParseLine   = regexp.MustCompile(`^template: [^:]*:(\d+):\s*`)
+	lintActions     = regexp.MustCompile(`(?s){{.*?}}`)
+	lintInvisible   = regexp.MustCompile(`(?is)<!--.*?-->|<(script|style)\b.*?</(script|style)\s*>|<!doctype[^>]*>`)
+	lintTag         = regexp.MustCompile(`<(/?)([a-zA-Z][a-zA-Z0-9-]*)([^>]*?)(/?)>`)
+	lintExternalSrc = regexp.MustCompile(`(?i)\bsrc\s*=\s*["']?(https?:)?//`)
+	lintAlt         = regexp.MustCompile(`(?i)\balt\s*=`)
+)
+
+// Elements that never have a closing tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true, "input": true,
+	"link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// Elements whose closing tag may be omitted.
+var optionalCloseElements = map[string]bool{
+	"p": true, "li": true, "td": true, "th": true, "tr": true, "thead": true, "tbody": true, "tfoot": true,
+	"option": true, "dt": true, "dd": true, "colgroup": true,
+}
+
+// Functions of sprig that listmonk registers for templates, all but env and expandenv. Only a subset is
+// implemented by the local renderer, the rest are known to the linter so that it does not reject them.
+var sprigFuncNames = []string{
+	"ago", "date", "date_in_zone", "date_modify", "dateInZone", "dateModify", "duration", "durationRound",
+	"htmlDate", "htmlDateInZone", "must_date_modify", "mustDateModify", "mustToDate", "now", "toDate", "unixEpoch",
+	"abbrev", "abbrevboth", "trunc", "trim", "upper", "lower", "title", "untitle", "substr", "repeat", "trimall",
+	"trimAll", "trimSuffix", "trimPrefix", "nospace", "initials", "randAlphaNum", "randAlpha", "randAscii",
+	"randNumeric", "swapcase", "shuffle", "snakecase", "camelcase", "kebabcase", "wrap", "wrapWith", "contains",
+	"hasPrefix", "hasSuffix", "quote", "squote", "cat", "indent", "nindent", "replace", "plural", "sha1sum",
+	"sha256sum", "adler32sum", "toString", "atoi", "int64", "int", "float64", "seq", "toDecimal", "split",
+	"splitList", "splitn", "toStrings", "until", "untilStep", "add1", "add", "sub", "div", "mod", "mul", "randInt",
+	"add1f", "addf", "subf", "divf", "mulf", "biggest", "max", "min", "maxf", "minf", "ceil", "floor", "round",
+	"join", "sortAlpha", "default", "empty", "coalesce", "all", "any", "compact", "mustCompact", "fromJson",
+	"toJson", "toPrettyJson", "toRawJson", "mustFromJson", "mustToJson", "mustToPrettyJson", "mustToRawJson",
+	"ternary", "deepCopy", "mustDeepCopy", "typeOf", "typeIs", "typeIsLike", "kindOf", "kindIs", "deepEqual",
+	"getHostByName", "base", "dir", "clean", "ext", "isAbs", "osBase", "osClean", "osDir", "osExt", "osIsAbs",
+	"b64enc", "b64dec", "b32enc", "b32dec", "tuple", "list", "dict", "get", "set", "unset", "hasKey", "pluck",
+	"keys", "pick", "omit", "merge", "mergeOverwrite", "mustMerge", "mustMergeOverwrite", "values", "append",
+	"push", "mustAppend", "mustPush", "prepend", "mustPrepend", "first", "mustFirst", "rest", "mustRest", "last",
+	"mustLast", "initial", "mustInitial", "reverse", "mustReverse", "uniq", "mustUniq", "without", "mustWithout",
+	"has", "mustHas", "slice", "mustSlice", "concat", "dig", "chunk", "mustChunk", "bcrypt", "htpasswd",
+	"genPrivateKey", "derivePassword", "buildCustomCert", "genCA", "genCAWithKey", "genSelfSignedCert",
+	"genSelfSignedCertWithKey", "genSignedCert", "genSignedCertWithKey", "encryptAES", "decryptAES", "randBytes",
+	"uuidv4", "semver", "semverCompare", "fail", "regexMatch", "mustRegexMatch", "regexFindAll",
+	"mustRegexFindAll", "regexFind", "mustRegexFind", "regexReplaceAll", "mustRegexReplaceAll",
+	"regexReplaceAllLiteral", "mustRegexReplaceAllLiteral", "regexSplit", "mustRegexSplit", "regexQuoteMeta",
+	"urlParse", "urlJoin",
+}
+
+// Check a template body for problems before it is uploaded. The body is parsed with listmonk's template
+// functions, campaign templates are checked for the content block and an unsubscribe link, and the HTML is
+// checked for unbalanced tags, external images without alt text and its size.
+func LintTemplate(body string, kind TemplateType) []LintIssue {
+	issues := []LintIssue{}
+
+	funcs := NewRenderer(RendererConfig{}).funcs()
+	for _, name := range sprigFuncNames {
+		if _, ok := funcs[name]; !ok {
+			funcs[name] = func(...any) any { return nil }
+		}
+	}
+	if _, err := template.New("lint").Funcs(funcs).Parse(rewriteTemplate(body)); err != nil {
+		issue := LintIssue{Severity: LintError, Message: err.Error()}
+		if match := lintParseLine.FindStringSubmatch(issue.Message); match != nil {
+			fmt.Sscan(match[1], &issue.Line)
+			issue.Message = strings.TrimPrefix(issue.Message, match[0])
+		}
+		if match := lintUnknownFunc.FindStringSubmatch(issue.Message); match != nil {
+			issue.Message = fmt.Sprintf("unknown function %q", match[1])
+		}
+		issues = append(issues, issue)
+	}
+
+	if kind == TemplateTypeCampaign || kind == TemplateTypeCampaignVisual {
+		if !lintContent.MatchString(body) {
+			issues = append(issues, LintIssue{Severity: LintError, Message: `campaign templates must include {{ template "content" . }}`})
+		}
+		if !lintUnsubscribe.MatchString(body) {
+			issues = append(issues, LintIssue{Severity: LintWarning, Message: "campaign templates should include an UnsubscribeURL link"})
+		}
+	}
+
+	issues = append(issues, lintHTML(body)...)
+
+	if len(body) > MaxTemplateSize {
+		issues = append(issues, LintIssue{Severity: LintWarning, Message: fmt.Sprintf("body is %d bytes, messages over %d bytes are clipped by some clients", len(body), MaxTemplateSize)})
+	}
+	return issues
+}
+
+// Whether any of the issues is an error.
+func hasLintErrors(issues []LintIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == LintError {
+			return true
+		}
+	}
+	return false
+}
+
+func lintHTML(body string) []LintIssue {
+	// Blank out template actions, comments and scripts while keeping offsets intact for line numbers
+	blank := func(match string) string {
+		return strings.Map(func(r rune) rune {
+			if r == '\n' {
+				return r
+			}
+			return ' '
+		}, match)
+	}
+	markup := lintActions.ReplaceAllStringFunc(body, blank)
+	markup = lintInvisible.ReplaceAllStringFunc(markup, blank)
+	line := func(offset int) int {
+		return strings.Count(markup[:offset], "\n") + 1
+	}
+
+	type open struct {
+		name string
+		line int
+	}
+	issues := []LintIssue{}
+	stack := []open{}
+
+	for _, match := range lintTag.FindAllStringSubmatchIndex(markup, -1) {
+		closing := match[3] > match[2]
+		name := strings.ToLower(markup[match[4]:match[5]])
+		attrs := markup[match[6]:match[7]]
+		selfClosing := match[9] > match[8]
+
+		if name == "img" && lintExternalSrc.MatchString(attrs) && !lintAlt.MatchString(attrs) {
+			issues = append(issues, LintIssue{Severity: LintWarning, Line: line(match[0]), Message: "external image without alt text"})
+		}
+		if voidElements[name] || selfClosing {
+			continue
+		}
+		if !closing {
+			stack = append(stack, open{name: name, line: line(match[0])})
+			continue
+		}
+
+		index := -1
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].name == name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			issues = append(issues, LintIssue{Severity: LintWarning, Line: line(match[0]), Message: fmt.Sprintf("unexpected closing tag </%s>", name)})
+			continue
+		}
+		for _, unclosed := range stack[index+1:] {
+			if !optionalCloseElements[unclosed.name] {
+				issues = append(issues, LintIssue{Severity: LintWarning, Line: unclosed.line, Message: fmt.Sprintf("unclosed tag <%s>", unclosed.name)})
+			}
+		}
+		stack = stack[:index]
+	}
+
+	for _, unclosed := range stack {
+		if !optionalCloseElements[unclosed.name] {
+			issues = append(issues, LintIssue{Severity: LintWarning, Line: unclosed.line, Message: fmt.Sprintf("unclosed tag <%s>", unclosed.name)})
+		}
+	}
+	return issues
+}
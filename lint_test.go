@@ -0,0 +1,86 @@
+package listmonkgo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+func TestLintTemplate(t *testing.T) {
+	valid := `<html><body>
+<p>Hello
+{{ template "content" . }}
+<img src="https://example.com/logo.png" alt="Logo">
+<a href="{{ UnsubscribeURL }}">{{ L.T "email.unsub" }}</a>
+</body></html>`
+	if issues := listmonkgo.LintTemplate(valid, listmonkgo.TemplateTypeCampaign); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+
+	invalid := `<html><body>
+<div><span>{{ Shout .Subscriber.Name }}</div>
+<img src="https://example.com/logo.png">
+</body>`
+	issues := listmonkgo.LintTemplate(invalid, listmonkgo.TemplateTypeCampaign)
+	messages := []string{}
+	for _, issue := range issues {
+		messages = append(messages, issue.String())
+	}
+	expected := []string{
+		`error: line 2: unknown function "Shout"`,
+		`error: campaign templates must include {{ template "content" . }}`,
+		"warning: campaign templates should include an UnsubscribeURL link",
+		"warning: line 2: unclosed tag <span>",
+		"warning: line 3: external image without alt text",
+		"warning: line 1: unclosed tag <html>",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected issues:\n%s", strings.Join(messages, "\n"))
+	}
+
+	sprig := `<p>{{ .Tx.Data.order | toJson | b64enc }} {{ regexReplaceAll "[0-9]" .Subscriber.Name "#" }}</p>`
+	if issues := listmonkgo.LintTemplate(sprig, listmonkgo.TemplateTypeTx); len(issues) != 0 {
+		t.Errorf("expected sprig functions listmonk registers to be accepted, got %v", issues)
+	}
+	if issues := listmonkgo.LintTemplate("<p>{{ .Tx.Data.order }}</p>", listmonkgo.TemplateTypeTx); len(issues) != 0 {
+		t.Errorf("expected tx templates not to require campaign blocks, got %v", issues)
+	}
+	if issues := listmonkgo.LintTemplate(strings.Repeat("a", listmonkgo.MaxTemplateSize+1), listmonkgo.TemplateTypeTx); len(issues) != 1 {
+		t.Errorf("expected oversized body to be flagged, got %v", issues)
+	}
+}
+
+func TestTemplateLintingPreflight(t *testing.T) {
+	requests := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data": {"id": 1}}`))
+	})
+	client := createTestClient(t, handler)
+	lintingClient := listmonkgo.New(
+		listmonkgo.WithBaseURL(createTestServerURL(t, handler)),
+		listmonkgo.WithTemplateLinting(true),
+	)
+
+	params := &listmonkgo.CreateTemplateParams{Name: "Broken", Type: listmonkgo.TemplateTypeCampaign, Body: "<p>{{ .Broken</p>"}
+
+	_, err := lintingClient.CreateTemplate(context.Background(), params)
+	lintErr := &listmonkgo.TemplateLintError{}
+	if !errors.As(err, &lintErr) || len(lintErr.Issues) == 0 {
+		t.Fatalf("expected a lint error, got %v", err)
+	}
+	if requests != 0 {
+		t.Error("expected the template not to be sent")
+	}
+
+	if _, err := client.CreateTemplate(context.Background(), params); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Error("expected linting to be opt-in")
+	}
+}
@@ -0,0 +1,63 @@
+package listmonkgo_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+func TestUploadMedia(t *testing.T) {
+	uploads := map[string]string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/media", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+		uploads[header.Filename] = header.Header.Get("Content-Type") + ":" + string(data)
+		w.Write([]byte(`{"data": {"id": 3, "filename": "` + header.Filename + `", "content_type": "image/png", "url": "http://localhost/uploads/` + header.Filename + `", "thumb_url": "http://localhost/uploads/thumb_` + header.Filename + `", "provider": "filesystem"}}`))
+	})
+	client := createTestClient(t, mux)
+	ctx := context.Background()
+
+	media, err := client.UploadMedia(ctx, "images/logo.png", strings.NewReader("png"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if media.ID != 3 || media.URL != "http://localhost/uploads/logo.png" || media.Provider != "filesystem" {
+		t.Errorf("unexpected media %+v", media)
+	}
+	if uploads["logo.png"] != "image/png:png" {
+		t.Errorf("expected logo.png uploaded as image/png, got %q", uploads["logo.png"])
+	}
+
+	// Files without a known extension are sniffed
+	if _, err := client.UploadMedia(ctx, "notes", strings.NewReader("plain words"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if uploads["notes"] != "text/plain; charset=utf-8:plain words" {
+		t.Errorf("expected notes uploaded as plain text, got %q", uploads["notes"])
+	}
+
+	opts := &listmonkgo.UploadMediaOptions{AllowedExtensions: []string{"png", ".JPG"}, MaxSize: 4}
+	if _, err := client.UploadMedia(ctx, "photo.jpg", strings.NewReader("jpg"), opts); err != nil {
+		t.Errorf("expected photo.jpg to be allowed, got %v", err)
+	}
+	if _, err := client.UploadMedia(ctx, "script.svg", strings.NewReader("svg"), opts); !errors.Is(err, listmonkgo.ErrInvalidMedia) {
+		t.Errorf("expected disallowed extension error, got %v", err)
+	}
+	if _, err := client.UploadMedia(ctx, "large.png", strings.NewReader("too large"), opts); !errors.Is(err, listmonkgo.ErrInvalidMedia) {
+		t.Errorf("expected size error, got %v", err)
+	}
+	if _, ok := uploads["large.png"]; ok {
+		t.Error("expected oversized file not to be uploaded")
+	}
+}
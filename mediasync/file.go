@@ -0,0 +1,58 @@
+// Package mediasync mirrors a directory of files, such as campaign images, to the listmonk media library.
+package mediasync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type File struct {
+	// Name of the file, used as the media filename.
+	Name string
+	// Path of the local file.
+	Path string
+	// Hex encoded SHA-256 hash of the contents.
+	Hash string
+	Size int64
+}
+
+// Read every regular file in a directory. Subdirectories and hidden files are skipped.
+func ScanDir(dir string) ([]*File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []*File{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		hash, size, err := hashFile(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &File{Name: entry.Name(), Path: path, Hash: hash, Size: size})
+	}
+	return files, nil
+}
+
+func hashFile(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
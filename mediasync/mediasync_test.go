@@ -0,0 +1,206 @@
+package mediasync_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+	"github.com/canpacis/listmonk-go/mediasync"
+)
+
+func TestSync(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"logo.png":   "logo",
+		"banner.png": "new banner",
+		"hero.jpg":   "hero",
+		".DS_Store":  "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "drafts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	remote := map[string]string{"1": "logo", "2": "old banner", "3": "old"}
+	names := map[string]string{"1": "logo.png", "2": "banner.png", "3": "old.png"}
+	calls := []string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/media", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [
+			{"id": 1, "filename": "logo.png"},
+			{"id": 2, "filename": "banner.png"},
+			{"id": 3, "filename": "old.png"}
+		]}`))
+	})
+	mux.HandleFunc("GET /api/media/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		fmt.Fprintf(w, `{"data": {"id": %s, "filename": %q, "url": "/uploads/%s"}}`, id, names[id], names[id])
+	})
+	mux.HandleFunc("GET /uploads/{name}", func(w http.ResponseWriter, r *http.Request) {
+		for id, name := range names {
+			if name == r.PathValue("name") {
+				w.Write([]byte(remote[id]))
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("DELETE /api/media/{id}", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "DELETE "+r.PathValue("id"))
+		w.Write([]byte(`{"data": true}`))
+	})
+	mux.HandleFunc("POST /api/media", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		calls = append(calls, fmt.Sprintf("POST %s %s", header.Filename, data))
+		w.Write([]byte(`{"data": {"id": 10}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	scanned, err := mediasync.ScanDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scanned) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(scanned))
+	}
+
+	client := listmonkgo.New(listmonkgo.WithBaseURL(server.URL))
+	plan, err := mediasync.New(client, mediasync.Config{}).Plan(context.Background(), scanned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Changes[0].Action != mediasync.ConflictAction || len(plan.Changes) != 3 {
+		t.Errorf("expected changed media to be kept without replace, got:\n%s", plan)
+	}
+
+	syncer := mediasync.New(client, mediasync.Config{Prune: true, Replace: true})
+	plan, err = syncer.Plan(context.Background(), scanned)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"~ banner.png: replace media 2",
+		"+ hero.jpg: upload 4 bytes",
+		"  logo.png: media 1 is up to date",
+		"- old.png: delete media 3",
+		"",
+	}, "\n")
+	if plan.String() != expected {
+		t.Errorf("unexpected plan:\n%s", plan)
+	}
+
+	if err := syncer.Apply(context.Background(), plan); err != nil {
+		t.Fatal(err)
+	}
+	expectedCalls := []string{"POST banner.png new banner", "DELETE 2", "POST hero.jpg hero", "DELETE 3"}
+	if strings.Join(calls, "\n") != strings.Join(expectedCalls, "\n") {
+		t.Errorf("unexpected calls:\n%s", strings.Join(calls, "\n"))
+	}
+	if plan.Changes[1].MediaID != 10 {
+		t.Errorf("expected uploaded media ID to be recorded, got %d", plan.Changes[1].MediaID)
+	}
+}
+
+func TestDownloadMedia(t *testing.T) {
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.Header.Get("Authorization")) > 0 {
+			t.Error("expected credentials not to be sent to external storage")
+		}
+		w.Write([]byte("backup"))
+	}))
+	defer storage.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/media/{id}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data": {"id": 1, "filename": "a.png", "url": "%s/a.png"}}`, storage.URL)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := listmonkgo.New(listmonkgo.WithBaseURL(server.URL), listmonkgo.WithToken("secret"))
+	builder := new(strings.Builder)
+	n, err := client.DownloadMedia(context.Background(), 1, builder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 6 || builder.String() != "backup" {
+		t.Errorf("unexpected download %d %q", n, builder.String())
+	}
+}
+
+func TestPlanMatchesListmonkFilenames(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"summer sale.png": "sale", "logo.png": "new logo"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The sale image was uploaded as summer-sale.png while an older file had that name, the logo was
+	// replaced by a file under another name
+	remote := map[string]string{"1": "old sale", "2": "sale", "3": "other logo", "4": "new logo"}
+	names := map[string]string{"1": "summer-sale.png", "2": "summer-sale_1.png", "3": "logo_dark.png", "4": "logo_a1B2c3.png"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/media", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [
+			{"id": 1, "filename": "summer-sale.png"},
+			{"id": 2, "filename": "summer-sale_1.png"},
+			{"id": 3, "filename": "logo_dark.png"},
+			{"id": 4, "filename": "logo_a1B2c3.png"}
+		]}`))
+	})
+	mux.HandleFunc("GET /api/media/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		fmt.Fprintf(w, `{"data": {"id": %s, "filename": %q, "url": "/uploads/%s"}}`, id, names[id], names[id])
+	})
+	mux.HandleFunc("GET /uploads/{name}", func(w http.ResponseWriter, r *http.Request) {
+		for id, name := range names {
+			if name == r.PathValue("name") {
+				w.Write([]byte(remote[id]))
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	scanned, err := mediasync.ScanDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := listmonkgo.New(listmonkgo.WithBaseURL(server.URL))
+	plan, err := mediasync.New(client, mediasync.Config{Prune: true}).Plan(context.Background(), scanned)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"  logo.png: media 4 is up to date",
+		"  summer sale.png: media 2 is up to date",
+		"- summer-sale.png: delete media 1",
+		"- logo_dark.png: delete media 3",
+		"",
+	}, "\n")
+	if plan.String() != expected {
+		t.Errorf("unexpected plan:\n%s", plan)
+	}
+}
@@ -0,0 +1,219 @@
+package mediasync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+type Action string
+
+const (
+	UploadAction    Action = "upload"
+	UnchangedAction Action = "unchanged"
+	// The media file has the same name but different contents, the new file is uploaded and the old one is
+	// deleted.
+	ReplaceAction Action = "replace"
+	// The media file has the same name but different contents and is kept since replacing is disabled.
+	ConflictAction Action = "conflict"
+	DeleteAction   Action = "delete"
+)
+
+type Change struct {
+	Action Action
+	// Filename of the media.
+	Name string
+	// Local file, nil when the media is deleted.
+	File *File
+	// ID of the existing media, set to the uploaded media once applied.
+	MediaID int
+}
+
+type Plan struct {
+	Changes []Change
+}
+
+// Human readable report of the plan, one line per file.
+func (p *Plan) String() string {
+	builder := new(strings.Builder)
+	for _, change := range p.Changes {
+		switch change.Action {
+		case UploadAction:
+			fmt.Fprintf(builder, "+ %s: upload %d bytes\n", change.Name, change.File.Size)
+		case UnchangedAction:
+			fmt.Fprintf(builder, "  %s: media %d is up to date\n", change.Name, change.MediaID)
+		case ReplaceAction:
+			fmt.Fprintf(builder, "~ %s: replace media %d\n", change.Name, change.MediaID)
+		case ConflictAction:
+			fmt.Fprintf(builder, "! %s: media %d differs from the local file\n", change.Name, change.MediaID)
+		case DeleteAction:
+			fmt.Fprintf(builder, "- %s: delete media %d\n", change.Name, change.MediaID)
+		}
+	}
+	return builder.String()
+}
+
+// Whether applying the plan would change anything.
+func (p *Plan) HasChanges() bool {
+	for _, change := range p.Changes {
+		if change.Action != UnchangedAction && change.Action != ConflictAction {
+			return true
+		}
+	}
+	return false
+}
+
+type Config struct {
+	// Delete media that is not in the directory.
+	Prune bool
+	// Replace media whose contents differ from the local file of the same name. Links to the old media break
+	// and listmonk adds a suffix to the name of the upload since the old file still exists when it is uploaded.
+	Replace bool
+	// Optional validation of uploaded files.
+	Upload *listmonkgo.UploadMediaOptions
+}
+
+type Syncer struct {
+	client *listmonkgo.Client
+	config Config
+}
+
+func New(client *listmonkgo.Client, config Config) *Syncer {
+	return &Syncer{client: client, config: config}
+}
+
+// Compare the files with the media library by filename and content hash without changing anything. Media
+// that listmonk could have stored a local file as is downloaded to hash its contents: listmonk replaces
+// spaces in uploaded filenames with dashes and adds a suffix, eg: logo_1.png, when the name is taken.
+// Suffixed media only matches a file with the same contents, conflicts are only reported for the exact name.
+func (s *Syncer) Plan(ctx context.Context, files []*File) (*Plan, error) {
+	list, err := s.client.GetMediaList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{}
+	matched := map[int]bool{}
+	for _, file := range files {
+		name := storedName(file.Name)
+		exact, suffixed := []int{}, []int{}
+		for _, media := range list {
+			if media.Filename == name {
+				exact = append(exact, media.ID)
+			} else if isSuffixed(media.Filename, name) {
+				suffixed = append(suffixed, media.ID)
+			}
+		}
+
+		change := Change{Action: UploadAction, Name: file.Name, File: file}
+		if len(exact) > 0 {
+			change.Action, change.MediaID = ConflictAction, exact[0]
+			if s.config.Replace {
+				change.Action = ReplaceAction
+			}
+		}
+		for _, id := range append(exact, suffixed...) {
+			hash, err := s.hash(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file.Name, err)
+			}
+			if hash == file.Hash {
+				change.Action, change.MediaID = UnchangedAction, id
+				break
+			}
+		}
+		if change.Action != UploadAction {
+			matched[change.MediaID] = true
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	if !s.config.Prune {
+		return plan, nil
+	}
+	for _, media := range list {
+		if !matched[media.ID] {
+			plan.Changes = append(plan.Changes, Change{Action: DeleteAction, Name: media.Filename, MediaID: media.ID})
+		}
+	}
+	return plan, nil
+}
+
+var (
+	spaces      = regexp.MustCompile(`\s+`)
+	mediaSuffix = regexp.MustCompile(`^([0-9]+|[a-zA-Z0-9]{6})$`)
+)
+
+// Filename listmonk stores an upload as when the name is free.
+func storedName(name string) string {
+	return spaces.ReplaceAllString(strings.TrimSpace(name), "-")
+}
+
+// Whether filename is name with the suffix listmonk adds to uploads whose name is taken: a number on the
+// filesystem or a random string of six characters otherwise.
+func isSuffixed(filename, name string) bool {
+	ext := path.Ext(name)
+	prefix := strings.TrimSuffix(name, ext) + "_"
+	if !strings.HasPrefix(filename, prefix) || !strings.HasSuffix(filename, ext) {
+		return false
+	}
+	return mediaSuffix.MatchString(filename[len(prefix) : len(filename)-len(ext)])
+}
+
+// Upload, replace and delete the media in the plan. Unchanged and conflicting media is skipped.
+func (s *Syncer) Apply(ctx context.Context, plan *Plan) error {
+	for i, change := range plan.Changes {
+		switch change.Action {
+		case UploadAction:
+			id, err := s.upload(ctx, change.File)
+			if err != nil {
+				return fmt.Errorf("%s: %w", change.Name, err)
+			}
+			plan.Changes[i].MediaID = id
+		case ReplaceAction:
+			// The old file is only deleted once its replacement is uploaded
+			id, err := s.upload(ctx, change.File)
+			if err != nil {
+				return fmt.Errorf("%s: %w", change.Name, err)
+			}
+			plan.Changes[i].MediaID = id
+			if _, err := s.client.DeleteMedia(ctx, change.MediaID); err != nil {
+				return fmt.Errorf("%s: %w", change.Name, err)
+			}
+		case DeleteAction:
+			if _, err := s.client.DeleteMedia(ctx, change.MediaID); err != nil {
+				return fmt.Errorf("%s: %w", change.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Syncer) upload(ctx context.Context, file *File) (int, error) {
+	reader, err := os.Open(file.Path)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	media, err := s.client.UploadMedia(ctx, file.Name, reader, s.config.Upload)
+	if err != nil {
+		return 0, err
+	}
+	return media.ID, nil
+}
+
+func (s *Syncer) hash(ctx context.Context, id int) (string, error) {
+	hash := sha256.New()
+	if _, err := s.client.DownloadMedia(ctx, id, hash); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
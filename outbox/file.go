@@ -0,0 +1,198 @@
+package outbox
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+const (
+	pendingDir   = "pending"
+	deliveredDir = "delivered"
+	deadDir      = "dead"
+)
+
+// Keeps one JSON file per message in a directory, split into pending, delivered and dead subdirectories.
+// Files are written atomically so a crash never leaves a partial message behind.
+type FileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	for _, sub := range []string{pendingDir, deliveredDir, deadDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// SendTemplateParams encodes itself in listmonk's wire format, which cannot be read back, so messages
+// are stored with the plain struct encoding instead.
+type plainParams listmonkgo.SendTemplateParams
+
+type storedMessage struct {
+	ID          string      `json:"id"`
+	Params      plainParams `json:"params"`
+	Attempts    int         `json:"attempts"`
+	LastError   string      `json:"last_error"`
+	CreatedAt   time.Time   `json:"created_at"`
+	NextAttempt time.Time   `json:"next_attempt"`
+}
+
+func (s *FileStore) path(sub, id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return filepath.Join(s.dir, sub, hex.EncodeToString(sum[:])+".json")
+}
+
+func (s *FileStore) exists(sub, id string) bool {
+	_, err := os.Stat(s.path(sub, id))
+	return err == nil
+}
+
+func (s *FileStore) write(sub string, msg *Message) error {
+	data, err := json.Marshal(storedMessage{
+		ID:          msg.ID,
+		Params:      plainParams(msg.Params),
+		Attempts:    msg.Attempts,
+		LastError:   msg.LastError,
+		CreatedAt:   msg.CreatedAt,
+		NextAttempt: msg.NextAttempt,
+	})
+	if err != nil {
+		return err
+	}
+
+	path := s.path(sub, msg.ID)
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *FileStore) read(sub string) ([]*Message, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, sub, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	messages := []*Message{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		stored := storedMessage{}
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return nil, err
+		}
+		messages = append(messages, &Message{
+			ID:          stored.ID,
+			Params:      listmonkgo.SendTemplateParams(stored.Params),
+			Attempts:    stored.Attempts,
+			LastError:   stored.LastError,
+			CreatedAt:   stored.CreatedAt,
+			NextAttempt: stored.NextAttempt,
+		})
+	}
+	return messages, nil
+}
+
+func (s *FileStore) Add(ctx context.Context, msg *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.exists(pendingDir, msg.ID) || s.exists(deliveredDir, msg.ID) || s.exists(deadDir, msg.ID) {
+		return ErrDuplicate
+	}
+	return s.write(pendingDir, msg)
+}
+
+func (s *FileStore) Due(ctx context.Context, now time.Time, limit int) ([]*Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending, err := s.read(pendingDir)
+	if err != nil {
+		return nil, err
+	}
+	due := []*Message{}
+	for _, msg := range pending {
+		if !msg.NextAttempt.After(now) {
+			due = append(due, msg)
+		}
+	}
+	sortMessages(due)
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (s *FileStore) Update(ctx context.Context, msg *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.exists(pendingDir, msg.ID) {
+		return errors.New("message " + msg.ID + " is not pending")
+	}
+	return s.write(pendingDir, msg)
+}
+
+func (s *FileStore) Complete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Only the key is kept for delivered messages
+	if err := s.write(deliveredDir, &Message{ID: id}); err != nil {
+		return err
+	}
+	return removeIfExists(s.path(pendingDir, id))
+}
+
+func (s *FileStore) DeadLetter(ctx context.Context, msg *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.write(deadDir, msg); err != nil {
+		return err
+	}
+	return removeIfExists(s.path(pendingDir, msg.ID))
+}
+
+func (s *FileStore) DeadLetters(ctx context.Context) ([]*Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dead, err := s.read(deadDir)
+	if err != nil {
+		return nil, err
+	}
+	sortMessages(dead)
+	return dead, nil
+}
+
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
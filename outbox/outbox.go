@@ -0,0 +1,187 @@
+// Package outbox durably queues transactional messages and delivers them in the background with retries,
+// so that sends survive listmonk outages and process restarts.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+	"github.com/google/uuid"
+)
+
+type Config struct {
+	// Failed attempts after which a message is dead-lettered. Defaults to 5.
+	MaxAttempts int
+	// Delay before retrying after the given number of failed attempts. Defaults to exponential backoff
+	// starting at a second and capped at an hour.
+	Backoff func(attempts int) time.Duration
+	// Interval to check the store for due messages at. Defaults to a second.
+	PollInterval time.Duration
+	// Maximum number of concurrent sends. Defaults to 4.
+	Concurrency int
+	// Called after every failed attempt and store error, eg: for logging. The message is nil when the store
+	// failed to list due messages.
+	OnError func(msg *Message, err error)
+}
+
+func defaultBackoff(attempts int) time.Duration {
+	delay := time.Second << min(attempts-1, 12)
+	return min(delay, time.Hour)
+}
+
+type Outbox struct {
+	client *listmonkgo.Client
+	store  Store
+	config Config
+
+	wake     chan struct{}
+	mu       sync.Mutex
+	inflight map[string]bool
+	wg       sync.WaitGroup
+}
+
+func New(client *listmonkgo.Client, store Store, config Config) *Outbox {
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = 5
+	}
+	if config.Backoff == nil {
+		config.Backoff = defaultBackoff
+	}
+	if config.PollInterval == 0 {
+		config.PollInterval = time.Second
+	}
+	if config.Concurrency == 0 {
+		config.Concurrency = 4
+	}
+	return &Outbox{
+		client:   client,
+		store:    store,
+		config:   config,
+		wake:     make(chan struct{}, 1),
+		inflight: map[string]bool{},
+	}
+}
+
+// Queue a message for delivery. The key makes enqueuing idempotent: a message with a key that was already
+// enqueued is rejected with ErrDuplicate. An empty key generates a random one. Returns the key.
+func (o *Outbox) Enqueue(ctx context.Context, key string, params *listmonkgo.SendTemplateParams) (string, error) {
+	if err := params.Validate(); err != nil {
+		return "", err
+	}
+	if len(params.Attachments) > 0 {
+		return "", errors.New("messages with attachments cannot be persisted")
+	}
+	if len(key) == 0 {
+		key = uuid.NewString()
+	}
+
+	now := time.Now()
+	if err := o.store.Add(ctx, &Message{ID: key, Params: *params, CreatedAt: now, NextAttempt: now}); err != nil {
+		return "", err
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return key, nil
+}
+
+// Deliver messages until the context is cancelled. Store errors are reported to OnError and retried on the
+// next tick. On cancellation no new sends are started and Run returns once the sends in flight have finished.
+func (o *Outbox) Run(ctx context.Context) error {
+	ticker := time.NewTicker(o.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := o.dispatch(ctx)
+		if err != nil && ctx.Err() == nil && o.config.OnError != nil {
+			o.config.OnError(nil, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			o.wg.Wait()
+			return nil
+		case <-ticker.C:
+		case <-o.wake:
+		}
+	}
+}
+
+// Start sends for due messages that are not already in flight, up to the concurrency limit.
+func (o *Outbox) dispatch(ctx context.Context) error {
+	o.mu.Lock()
+	free := o.config.Concurrency - len(o.inflight)
+	limit := o.config.Concurrency + len(o.inflight)
+	o.mu.Unlock()
+	if free <= 0 {
+		return nil
+	}
+
+	due, err := o.store.Due(ctx, time.Now(), limit)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, msg := range due {
+		if free == 0 {
+			break
+		}
+		if o.inflight[msg.ID] {
+			continue
+		}
+		o.inflight[msg.ID] = true
+		free--
+
+		o.wg.Add(1)
+		go func() {
+			defer o.wg.Done()
+			// Sends in flight are drained on shutdown rather than cancelled
+			o.deliver(context.WithoutCancel(ctx), msg)
+
+			o.mu.Lock()
+			delete(o.inflight, msg.ID)
+			o.mu.Unlock()
+			select {
+			case o.wake <- struct{}{}:
+			default:
+			}
+		}()
+	}
+	return nil
+}
+
+func (o *Outbox) deliver(ctx context.Context, msg *Message) {
+	ok, err := o.client.SendTemplate(ctx, &msg.Params)
+	if err == nil && !ok {
+		err = errors.New("listmonk did not accept the message")
+	}
+	if err == nil {
+		if err := o.store.Complete(ctx, msg.ID); err != nil && o.config.OnError != nil {
+			o.config.OnError(msg, err)
+		}
+		return
+	}
+
+	msg.Attempts++
+	msg.LastError = err.Error()
+	if o.config.OnError != nil {
+		o.config.OnError(msg, err)
+	}
+
+	if msg.Attempts >= o.config.MaxAttempts {
+		err = o.store.DeadLetter(ctx, msg)
+	} else {
+		msg.NextAttempt = time.Now().Add(o.config.Backoff(msg.Attempts))
+		err = o.store.Update(ctx, msg)
+	}
+	if err != nil && o.config.OnError != nil {
+		o.config.OnError(msg, err)
+	}
+}
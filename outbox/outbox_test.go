@@ -0,0 +1,209 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+	"github.com/canpacis/listmonk-go/outbox"
+)
+
+func params(email string) *listmonkgo.SendTemplateParams {
+	return &listmonkgo.SendTemplateParams{
+		SubscriberEmail: email,
+		TemplateID:      1,
+		Headers:         http.Header{"X-Tag": {"a", "b"}},
+		Data:            map[string]any{"order": "A1"},
+	}
+}
+
+func eventually(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestOutboxRetriesAndDeadLetters(t *testing.T) {
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		key := string(body)
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[key]++
+		// The first recipient recovers after two failures, the second never does
+		if attempts[key] <= 2 || strings.Contains(key, "bad@example.com") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"message": "unavailable"}`))
+			return
+		}
+		w.Write([]byte(`{"data": true}`))
+	}))
+	defer server.Close()
+
+	stores := map[string]func(t *testing.T) outbox.Store{
+		"memory": func(t *testing.T) outbox.Store { return outbox.NewMemoryStore() },
+		"file": func(t *testing.T) outbox.Store {
+			store, err := outbox.NewFileStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return store
+		},
+	}
+
+	for name, create := range stores {
+		t.Run(name, func(t *testing.T) {
+			mu.Lock()
+			clear(attempts)
+			mu.Unlock()
+
+			store := create(t)
+			box := outbox.New(listmonkgo.New(listmonkgo.WithBaseURL(server.URL)), store, outbox.Config{
+				MaxAttempts:  3,
+				Backoff:      func(int) time.Duration { return time.Millisecond },
+				PollInterval: time.Millisecond,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() { done <- box.Run(ctx) }()
+
+			if _, err := box.Enqueue(ctx, "order-1", params("ada@example.com")); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := box.Enqueue(ctx, "order-2", params("bad@example.com")); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := box.Enqueue(ctx, "order-1", params("ada@example.com")); !errors.Is(err, outbox.ErrDuplicate) {
+				t.Errorf("expected duplicate error, got %v", err)
+			}
+
+			eventually(t, func() bool {
+				dead, _ := store.DeadLetters(ctx)
+				due, _ := store.Due(ctx, time.Now().Add(time.Hour), 10)
+				return len(dead) == 1 && len(due) == 0
+			})
+			cancel()
+			if err := <-done; err != nil {
+				t.Fatal(err)
+			}
+
+			dead, _ := store.DeadLetters(context.Background())
+			if dead[0].ID != "order-2" || dead[0].Attempts != 3 || dead[0].LastError != "unavailable" {
+				t.Errorf("unexpected dead letter %+v", dead[0])
+			}
+			if values := dead[0].Params.Headers.Values("X-Tag"); len(values) != 2 {
+				t.Errorf("expected headers to survive the store, got %v", dead[0].Params.Headers)
+			}
+			if _, err := box.Enqueue(context.Background(), "order-1", params("ada@example.com")); !errors.Is(err, outbox.ErrDuplicate) {
+				t.Errorf("expected delivered keys to be remembered, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOutboxDrainsOnShutdown(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var delivered atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		delivered.Store(true)
+		w.Write([]byte(`{"data": true}`))
+	}))
+	defer server.Close()
+
+	store := outbox.NewMemoryStore()
+	box := outbox.New(listmonkgo.New(listmonkgo.WithBaseURL(server.URL)), store, outbox.Config{PollInterval: time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- box.Run(ctx) }()
+	if _, err := box.Enqueue(ctx, "", params("ada@example.com")); err != nil {
+		t.Fatal(err)
+	}
+
+	<-started
+	cancel()
+	select {
+	case <-done:
+		t.Fatal("expected Run to wait for the send in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !delivered.Load() {
+		t.Error("expected the message to be delivered")
+	}
+	if due, _ := store.Due(context.Background(), time.Now().Add(time.Hour), 10); len(due) != 0 {
+		t.Errorf("expected the message to be completed, got %v", due)
+	}
+}
+
+// Fails to list due messages until it is healed.
+type flakyStore struct {
+	outbox.Store
+	failures atomic.Int32
+}
+
+func (s *flakyStore) Due(ctx context.Context, now time.Time, limit int) ([]*outbox.Message, error) {
+	if s.failures.Add(-1) >= 0 {
+		return nil, errors.New("store is locked")
+	}
+	return s.Store.Due(ctx, now, limit)
+}
+
+func TestOutboxSurvivesStoreErrors(t *testing.T) {
+	var delivered atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered.Store(true)
+		w.Write([]byte(`{"data": true}`))
+	}))
+	defer server.Close()
+
+	store := &flakyStore{Store: outbox.NewMemoryStore()}
+	store.failures.Store(3)
+	var storeErrors atomic.Int32
+	box := outbox.New(listmonkgo.New(listmonkgo.WithBaseURL(server.URL)), store, outbox.Config{
+		PollInterval: time.Millisecond,
+		OnError: func(msg *outbox.Message, err error) {
+			if msg == nil {
+				storeErrors.Add(1)
+			}
+		},
+	})
+	if _, err := box.Enqueue(context.Background(), "", params("ada@example.com")); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- box.Run(ctx) }()
+	eventually(t, delivered.Load)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if storeErrors.Load() != 3 {
+		t.Errorf("expected 3 store errors to be reported, got %d", storeErrors.Load())
+	}
+}
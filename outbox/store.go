@@ -0,0 +1,136 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+// Returned by Store.Add when a message with the same idempotency key was already enqueued.
+var ErrDuplicate = errors.New("a message with this idempotency key was already enqueued")
+
+type Message struct {
+	// Idempotency key of the message.
+	ID     string
+	Params listmonkgo.SendTemplateParams
+	// Number of failed delivery attempts.
+	Attempts int
+	// Error of the last failed attempt.
+	LastError   string
+	CreatedAt   time.Time
+	NextAttempt time.Time
+}
+
+// Persists messages across restarts. Implementations must be safe for concurrent use and remember the keys
+// of delivered and dead-lettered messages so that re-enqueuing them is rejected.
+type Store interface {
+	// Add a pending message, or return ErrDuplicate if its key is known.
+	Add(ctx context.Context, msg *Message) error
+	// Pending messages whose next attempt is due, oldest first.
+	Due(ctx context.Context, now time.Time, limit int) ([]*Message, error)
+	// Save the attempts and schedule of a pending message.
+	Update(ctx context.Context, msg *Message) error
+	// Mark a pending message as delivered.
+	Complete(ctx context.Context, id string) error
+	// Move a pending message to the dead letters.
+	DeadLetter(ctx context.Context, msg *Message) error
+	// Messages that failed too many times.
+	DeadLetters(ctx context.Context) ([]*Message, error)
+}
+
+// Keeps messages in memory. Messages are lost when the process exits.
+type MemoryStore struct {
+	mu        sync.Mutex
+	pending   map[string]*Message
+	delivered map[string]bool
+	dead      map[string]*Message
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		pending:   map[string]*Message{},
+		delivered: map[string]bool{},
+		dead:      map[string]*Message{},
+	}
+}
+
+func (s *MemoryStore) Add(ctx context.Context, msg *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending[msg.ID] != nil || s.delivered[msg.ID] || s.dead[msg.ID] != nil {
+		return ErrDuplicate
+	}
+	copied := *msg
+	s.pending[msg.ID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) Due(ctx context.Context, now time.Time, limit int) ([]*Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	due := []*Message{}
+	for _, msg := range s.pending {
+		if !msg.NextAttempt.After(now) {
+			copied := *msg
+			due = append(due, &copied)
+		}
+	}
+	sortMessages(due)
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, msg *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending[msg.ID] == nil {
+		return errors.New("message " + msg.ID + " is not pending")
+	}
+	copied := *msg
+	s.pending[msg.ID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) Complete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, id)
+	s.delivered[id] = true
+	return nil
+}
+
+func (s *MemoryStore) DeadLetter(ctx context.Context, msg *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, msg.ID)
+	copied := *msg
+	s.dead[msg.ID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) DeadLetters(ctx context.Context) ([]*Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dead := []*Message{}
+	for _, msg := range s.dead {
+		copied := *msg
+		dead = append(dead, &copied)
+	}
+	sortMessages(dead)
+	return dead, nil
+}
+
+func sortMessages(messages []*Message) {
+	slices.SortFunc(messages, func(a, b *Message) int {
+		if c := a.NextAttempt.Compare(b.NextAttempt); c != 0 {
+			return c
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+}
@@ -0,0 +1,452 @@
+package listmonkgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Default root URL of the stub links produced by a Renderer.
+const StubRootURL = "https://listmonk.example.com"
+
+type RendererConfig struct {
+	// Root URL used to build stub tracking, unsubscribe and archive URLs. Defaults to StubRootURL.
+	RootURL string
+	// Translations available to templates through {{ L.T "key" }}. Unknown keys render as the key itself.
+	Translations map[string]string
+	// Clock used by the Date and now helpers. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Renders listmonk templates offline with the same context and function names listmonk provides,
+// producing stub URLs instead of real tracking links.
+type Renderer struct {
+	config RendererConfig
+}
+
+func NewRenderer(config RendererConfig) *Renderer {
+	if len(config.RootURL) == 0 {
+		config.RootURL = StubRootURL
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	config.RootURL = strings.TrimSuffix(config.RootURL, "/")
+	return &Renderer{config: config}
+}
+
+// Transactional payload available to tx templates as {{ .Tx.Data.* }}.
+type TxMessage struct {
+	TemplateID int
+	Subject    string
+	Data       any
+}
+
+// Subscriber as listmonk exposes it to templates, where attributes are available as {{ .Subscriber.Attribs.* }}.
+type TemplateSubscriber struct {
+	ID        int
+	UUID      uuid.UUID
+	Email     string
+	Name      string
+	Status    SubscriberStatus
+	Attribs   map[string]any
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func newTemplateSubscriber(subscriber *Subscriber) *TemplateSubscriber {
+	if subscriber == nil {
+		return &TemplateSubscriber{Attribs: map[string]any{}}
+	}
+	return &TemplateSubscriber{
+		ID:        subscriber.ID,
+		UUID:      subscriber.UUID,
+		Email:     subscriber.Email,
+		Name:      subscriber.Name,
+		Status:    subscriber.Status,
+		Attribs:   subscriber.Attributes,
+		CreatedAt: subscriber.CreatedAt,
+		UpdatedAt: subscriber.UpdatedAt,
+	}
+}
+
+// First name of the subscriber.
+func (s *TemplateSubscriber) FirstName() string {
+	first, _, _ := strings.Cut(strings.TrimSpace(s.Name), " ")
+	return first
+}
+
+// Last name of the subscriber.
+func (s *TemplateSubscriber) LastName() string {
+	_, last, _ := strings.Cut(strings.TrimSpace(s.Name), " ")
+	return strings.TrimSpace(last)
+}
+
+// Context available to templates as the dot.
+type TemplateContext struct {
+	Subscriber *TemplateSubscriber
+	Campaign   *Campaign
+	Tx         *TxMessage
+}
+
+// Render a campaign with a campaign template. The campaign body is available as {{ template "content" . }}
+// and markdown bodies are converted to HTML first, as listmonk does.
+func (r *Renderer) RenderCampaign(tpl string, campaign *Campaign, subscriber *Subscriber) (string, error) {
+	body := campaign.Body
+	if campaign.ContentType == MarkdownCampaignContent {
+		html, err := MarkdownToHTML(body)
+		if err != nil {
+			return "", err
+		}
+		body = html
+	}
+
+	data := &TemplateContext{Subscriber: newTemplateSubscriber(subscriber), Campaign: campaign}
+	parsed, err := r.parse("base", tpl)
+	if err != nil {
+		return "", err
+	}
+	if _, err := parsed.New("content").Parse(rewriteTemplate(body)); err != nil {
+		return "", err
+	}
+	return execute(parsed, data)
+}
+
+// Render a transactional template and its subject with the given subscriber and data. Like listmonk, the
+// subject is rendered as plain text so that its data is not HTML escaped.
+func (r *Renderer) RenderTx(tpl *Template, subscriber *Subscriber, data any) (subject string, body string, err error) {
+	ctx := &TemplateContext{Subscriber: newTemplateSubscriber(subscriber), Tx: &TxMessage{TemplateID: tpl.ID, Subject: tpl.Subject, Data: data}}
+
+	subjectTpl, err := texttemplate.New("subject").Funcs(texttemplate.FuncMap(r.funcs())).Parse(rewriteTemplate(tpl.Subject))
+	if err != nil {
+		return "", "", err
+	}
+	if subject, err = execute(subjectTpl, ctx); err != nil {
+		return "", "", err
+	}
+
+	parsed, err := r.parse("body", tpl.Body)
+	if err != nil {
+		return "", "", err
+	}
+	if body, err = execute(parsed, ctx); err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
+
+func (r *Renderer) parse(name, tpl string) (*template.Template, error) {
+	return template.New(name).Funcs(r.funcs()).Parse(rewriteTemplate(tpl))
+}
+
+// Executes both html and text templates.
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+func execute(tpl executor, data *TemplateContext) (string, error) {
+	out := new(bytes.Buffer)
+	if err := tpl.Execute(out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// listmonk lets templates omit the dot argument of its URL functions and supports a url@TrackLink shorthand,
+// both are rewritten to regular calls before parsing.
+var templateRewrites = []struct {
+	expr    *regexp.Regexp
+	replace string
+}{
+	{regexp.MustCompile(`{{(\s+)?TrackLink(\s+)?"(.+?)"(\s+)?}}`), `{{ TrackLink "$3" . }}`},
+	{regexp.MustCompile(`(https?://.+?)@TrackLink`), `{{ TrackLink "$1" . }}`},
+	{regexp.MustCompile(`{{(\s+)?(TrackView|UnsubscribeURL|ManageURL|OptinURL|MessageURL)(\s+)?}}`), `{{ $2 . }}`},
+}
+
+func rewriteTemplate(tpl string) string {
+	for _, rewrite := range templateRewrites {
+		tpl = rewrite.expr.ReplaceAllString(tpl, rewrite.replace)
+	}
+	return tpl
+}
+
+// Looks up template translations.
+type Translator struct {
+	messages map[string]string
+}
+
+// Translate a key, falling back to the key itself.
+func (t *Translator) T(key string) string {
+	if message, ok := t.messages[key]; ok {
+		return message
+	}
+	return key
+}
+
+func (r *Renderer) funcs() template.FuncMap {
+	root := r.config.RootURL
+	ids := func(ctx *TemplateContext) (string, string) {
+		campaign, subscriber := "00000000-0000-0000-0000-000000000000", "00000000-0000-0000-0000-000000000000"
+		if ctx != nil && ctx.Campaign != nil {
+			campaign = ctx.Campaign.UUID.String()
+		}
+		if ctx != nil && ctx.Subscriber != nil {
+			subscriber = ctx.Subscriber.UUID.String()
+		}
+		return campaign, subscriber
+	}
+
+	funcs := templateHelpers(r.config.Now)
+	funcs["TrackLink"] = func(link string, ctx *TemplateContext) string {
+		campaign, subscriber := ids(ctx)
+		return fmt.Sprintf("%s/link/%s/%s?url=%s", root, campaign, subscriber, url.QueryEscape(link))
+	}
+	funcs["TrackView"] = func(ctx *TemplateContext) template.HTML {
+		campaign, subscriber := ids(ctx)
+		return template.HTML(fmt.Sprintf(`<img src="%s/campaign/%s/%s/px.png" alt="" />`, root, campaign, subscriber))
+	}
+	funcs["UnsubscribeURL"] = func(ctx *TemplateContext) string {
+		campaign, subscriber := ids(ctx)
+		return fmt.Sprintf("%s/subscription/%s/%s", root, campaign, subscriber)
+	}
+	funcs["ManageURL"] = func(ctx *TemplateContext) string {
+		campaign, subscriber := ids(ctx)
+		return fmt.Sprintf("%s/subscription/%s/%s?manage=true", root, campaign, subscriber)
+	}
+	funcs["OptinURL"] = func(ctx *TemplateContext) string {
+		_, subscriber := ids(ctx)
+		return fmt.Sprintf("%s/subscription/optin/%s", root, subscriber)
+	}
+	funcs["MessageURL"] = func(ctx *TemplateContext) string {
+		campaign, subscriber := ids(ctx)
+		return fmt.Sprintf("%s/campaign/%s/%s", root, campaign, subscriber)
+	}
+	funcs["ArchiveURL"] = func() string {
+		return root + "/archive"
+	}
+	funcs["RootURL"] = func() string {
+		return root
+	}
+	funcs["L"] = func() *Translator {
+		return &Translator{messages: r.config.Translations}
+	}
+	return funcs
+}
+
+// Helpers that do not depend on the message being rendered: listmonk's Date and Safe, and a subset of
+// the sprig functions listmonk ships with.
+func templateHelpers(now func() time.Time) template.FuncMap {
+	return template.FuncMap{
+		"Date": func(layout string) string {
+			if len(layout) == 0 {
+				layout = time.ANSIC
+			}
+			return now().Format(layout)
+		},
+		"Safe": func(html string) template.HTML {
+			return template.HTML(html)
+		},
+
+		"now": now,
+		"date": func(layout string, t time.Time) string {
+			return t.Format(layout)
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"title": func(s string) string {
+			words := strings.Fields(s)
+			for i, word := range words {
+				first, size := utf8.DecodeRuneInString(word)
+				words[i] = string(unicode.ToUpper(first)) + word[size:]
+			}
+			return strings.Join(words, " ")
+		},
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"repeat":     func(count int, s string) string { return strings.Repeat(s, count) },
+		"split":      func(sep, s string) []string { return strings.Split(s, sep) },
+		"join": func(sep string, items any) string {
+			switch items := items.(type) {
+			case []string:
+				return strings.Join(items, sep)
+			case []any:
+				parts := make([]string, len(items))
+				for i, item := range items {
+					parts[i] = fmt.Sprint(item)
+				}
+				return strings.Join(parts, sep)
+			}
+			return fmt.Sprint(items)
+		},
+		"trunc": func(length int, s string) string {
+			if runes := []rune(s); length >= 0 && len(runes) > length {
+				return string(runes[:length])
+			}
+			return s
+		},
+		"quote":    func(s any) string { return fmt.Sprintf("%q", fmt.Sprint(s)) },
+		"toString": func(v any) string { return fmt.Sprint(v) },
+		"default": func(fallback any, values ...any) any {
+			if len(values) == 0 || empty(values[0]) {
+				return fallback
+			}
+			return values[0]
+		},
+		"empty": empty,
+		"coalesce": func(values ...any) any {
+			for _, value := range values {
+				if !empty(value) {
+					return value
+				}
+			}
+			return nil
+		},
+		"ternary": func(yes, no any, condition bool) any {
+			if condition {
+				return yes
+			}
+			return no
+		},
+		"add": func(values ...any) int64 {
+			var sum int64
+			for _, value := range values {
+				sum += toInt64(value)
+			}
+			return sum
+		},
+		"sub": func(a, b any) int64 { return toInt64(a) - toInt64(b) },
+		"mul": func(a any, values ...any) int64 {
+			product := toInt64(a)
+			for _, value := range values {
+				product *= toInt64(value)
+			}
+			return product
+		},
+		"div": func(a, b any) (int64, error) {
+			divisor := toInt64(b)
+			if divisor == 0 {
+				return 0, errors.New("division by zero")
+			}
+			return toInt64(a) / divisor, nil
+		},
+		"addf": func(values ...any) float64 {
+			var sum float64
+			for _, value := range values {
+				sum += toFloat64(value)
+			}
+			return sum
+		},
+		"subf": func(a, b any) float64 { return toFloat64(a) - toFloat64(b) },
+		"mulf": func(a any, values ...any) float64 {
+			product := toFloat64(a)
+			for _, value := range values {
+				product *= toFloat64(value)
+			}
+			return product
+		},
+		"divf": func(a, b any) float64 { return toFloat64(a) / toFloat64(b) },
+		"list": func(items ...any) []any {
+			return items
+		},
+		"dict": func(pairs ...any) map[string]any {
+			dict := map[string]any{}
+			for i := 0; i+1 < len(pairs); i += 2 {
+				dict[fmt.Sprint(pairs[i])] = pairs[i+1]
+			}
+			return dict
+		},
+	}
+}
+
+// Converts numbers of any type, numeric strings and booleans the way sprig does: floats are truncated
+// and anything else is zero.
+func toInt64(value any) int64 {
+	if number, ok := value.(json.Number); ok {
+		value = string(number)
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return int64(v.Float())
+	case reflect.Bool:
+		if v.Bool() {
+			return 1
+		}
+	case reflect.String:
+		if i, err := strconv.ParseInt(v.String(), 0, 64); err == nil {
+			return i
+		}
+		if f, err := strconv.ParseFloat(v.String(), 64); err == nil {
+			return int64(f)
+		}
+	}
+	return 0
+}
+
+// Float counterpart of toInt64.
+func toFloat64(value any) float64 {
+	if number, ok := value.(json.Number); ok {
+		value = string(number)
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Bool:
+		if v.Bool() {
+			return 1
+		}
+	case reflect.String:
+		if f, err := strconv.ParseFloat(v.String(), 64); err == nil {
+			return f
+		}
+	}
+	return 0
+}
+
+func empty(value any) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case string:
+		return len(value) == 0
+	case bool:
+		return !value
+	case int:
+		return value == 0
+	case float64:
+		return value == 0
+	case []any:
+		return len(value) == 0
+	case map[string]any:
+		return len(value) == 0
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package listmonkgo_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+	"github.com/google/uuid"
+)
+
+func TestRenderCampaign(t *testing.T) {
+	renderer := listmonkgo.NewRenderer(listmonkgo.RendererConfig{
+		RootURL:      "https://lists.example.org/",
+		Translations: map[string]string{"email.unsub": "Unsubscribe"},
+		Now:          func() time.Time { return time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC) },
+	})
+
+	tpl := `<html><body>{{ template "content" . }}` +
+		`<a href="{{ UnsubscribeURL }}">{{ L.T "email.unsub" }}</a>{{ TrackView }}` +
+		`<footer>{{ Date "2006" }} {{ .Campaign.Subject | upper }}</footer></body></html>`
+	campaign := &listmonkgo.Campaign{
+		UUID:        uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Subject:     "news",
+		ContentType: listmonkgo.MarkdownCampaignContent,
+		Body:        `Hi {{ .Subscriber.FirstName }} from {{ .Subscriber.Attribs.city }}, [read](https://example.com@TrackLink)`,
+	}
+	subscriber := &listmonkgo.Subscriber{
+		UUID:       uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		Name:       "Ada Lovelace",
+		Attributes: map[string]any{"city": "London"},
+	}
+
+	out, err := renderer.RenderCampaign(tpl, campaign, subscriber)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := "11111111-1111-1111-1111-111111111111/22222222-2222-2222-2222-222222222222"
+	for _, expected := range []string{
+		"<p>Hi Ada from London, ",
+		`<a href="https://lists.example.org/link/` + ids + `?url=https%3A%2F%2Fexample.com">read</a>`,
+		`<a href="https://lists.example.org/subscription/` + ids + `">Unsubscribe</a>`,
+		`<img src="https://lists.example.org/campaign/` + ids + `/px.png" alt="" />`,
+		"<footer>2024 NEWS</footer>",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected output to contain %s, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestRenderTx(t *testing.T) {
+	type order struct {
+		Number string
+		Items  []string
+	}
+
+	renderer := listmonkgo.NewRenderer(listmonkgo.RendererConfig{})
+	tpl := &listmonkgo.Template{
+		Subject: `Order {{ .Tx.Data.Number }} shipped`,
+		Body:    `{{ .Subscriber.Email }}: {{ join ", " .Tx.Data.Items }} {{ default "n/a" .Subscriber.Attribs.phone }}`,
+	}
+
+	subject, body, err := renderer.RenderTx(tpl, &listmonkgo.Subscriber{Email: "ada@example.com"}, order{Number: "A1", Items: []string{"pen", "ink"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "Order A1 shipped" {
+		t.Errorf("unexpected subject %q", subject)
+	}
+	if body != "ada@example.com: pen, ink n/a" {
+		t.Errorf("unexpected body %q", body)
+	}
+
+	if _, _, err := renderer.RenderTx(&listmonkgo.Template{Body: "{{ Unknown }}"}, nil, nil); err == nil {
+		t.Error("expected unknown functions to fail")
+	}
+}
+
+func TestRenderTxSubjectIsNotEscaped(t *testing.T) {
+	renderer := listmonkgo.NewRenderer(listmonkgo.RendererConfig{})
+	tpl := &listmonkgo.Template{
+		Subject: `Welcome {{ .Tx.Data.company | title }} & {{ trunc 4 .Tx.Data.city }}`,
+		Body:    `{{ .Tx.Data.company }}`,
+	}
+
+	data := map[string]any{"company": "o'Brien <ltd>", "city": "Zürich"}
+	subject, body, err := renderer.RenderTx(tpl, nil, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "Welcome O'Brien <ltd> & Züri" {
+		t.Errorf("unexpected subject %q", subject)
+	}
+	if body != "o&#39;Brien &lt;ltd&gt;" {
+		t.Errorf("expected the body to be escaped, got %q", body)
+	}
+
+	subject, _, err = renderer.RenderTx(&listmonkgo.Template{Subject: `{{ title "élan vital" }}`}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "Élan Vital" {
+		t.Errorf("unexpected subject %q", subject)
+	}
+}
+
+func TestRenderTxArithmeticOnJSONNumbers(t *testing.T) {
+	data := map[string]any{}
+	if err := json.Unmarshal([]byte(`{"quantity": 3, "price": 2.5, "discount": "1"}`), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	renderer := listmonkgo.NewRenderer(listmonkgo.RendererConfig{})
+	tpl := &listmonkgo.Template{
+		Body: `{{ add .Tx.Data.quantity 1 }} {{ sub .Tx.Data.quantity .Tx.Data.discount }} {{ mul .Tx.Data.quantity 2 }} ` +
+			`{{ div .Tx.Data.quantity 2 }} {{ mulf .Tx.Data.quantity .Tx.Data.price }}`,
+	}
+	_, body, err := renderer.RenderTx(tpl, nil, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "4 2 6 1 7.5" {
+		t.Errorf("unexpected body %q", body)
+	}
+
+	if _, _, err := renderer.RenderTx(&listmonkgo.Template{Body: `{{ div 1 0 }}`}, nil, nil); err == nil {
+		t.Error("expected division by zero to fail")
+	}
+}
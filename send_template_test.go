@@ -0,0 +1,88 @@
+package listmonkgo_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestSendTemplateParamsMarshalJSON(t *testing.T) {
+	cases := map[string]listmonkgo.SendTemplateParams{
+		"subscriber_email": {
+			SubscriberEmail: "ada@example.com",
+			TemplateID:      3,
+		},
+		"subscriber_id": {
+			SubscriberID: 7,
+			TemplateID:   3,
+		},
+		"subscriber_emails": {
+			SubscriberEmails: []string{"ada@example.com", "alan@example.com"},
+			TemplateID:       3,
+		},
+		"subscriber_ids": {
+			SubscriberIDs: []int{7, 8},
+			TemplateID:    3,
+		},
+		"headers": {
+			SubscriberID: 7,
+			TemplateID:   3,
+			Headers: http.Header{
+				"X-Tag":      {"orders", "shipping"},
+				"Reply-To":   {"support@example.com"},
+				"X-Priority": {"1"},
+			},
+		},
+		"overrides": {
+			SubscriberEmail: "ada@example.com",
+			TemplateID:      3,
+			FromEmail:       "Shop <shop@example.com>",
+			Subject:         "Your order {{ .Tx.Data.order }}",
+			AltBody:         "Your order has shipped.",
+			Messenger:       "email",
+			ContentType:     listmonkgo.MarkdownTemplate,
+			Data:            map[string]any{"order": "A1", "items": []string{"pen", "ink"}},
+		},
+	}
+
+	for name, params := range cases {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := json.MarshalIndent(params, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			encoded = append(encoded, '\n')
+
+			// Pointers must encode the same as values
+			pointer, err := json.MarshalIndent(&params, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(encoded[:len(encoded)-1], pointer) {
+				t.Errorf("pointer encoding differs:\n%s", pointer)
+			}
+
+			golden := filepath.Join("testdata", "send_template", name+".json")
+			if *update {
+				if err := os.WriteFile(golden, encoded, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(encoded, expected) {
+				t.Errorf("expected:\n%s\ngot:\n%s", expected, encoded)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package listmonkgo
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Format of the data export of a single subscriber.
+type ExportFormat string
+
+const (
+	// Indented JSON document.
+	ExportFormatJSON ExportFormat = "json"
+	// Zip archive with a CSV file per record type.
+	ExportFormatZip ExportFormat = "zip"
+	// Human readable HTML report.
+	ExportFormatHTML ExportFormat = "html"
+)
+
+// Write the export of a subscriber's data in the given format, eg: to answer a subject access request.
+func (c *Client) ExportSubscriberTo(ctx context.Context, id int, w io.Writer, format ExportFormat) error {
+	export, err := c.ExportSubscriber(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case ExportFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(export)
+	case ExportFormatZip:
+		return writeExportZip(w, export)
+	case ExportFormatHTML:
+		return exportTemplate.Execute(w, export)
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func writeExportZip(w io.Writer, export *ExportSubscriberResponse) error {
+	profile := export.Profile
+	attributes, err := json.Marshal(profile.Attributes)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name string
+		rows [][]string
+	}{
+		{"profile.csv", [][]string{
+			{"id", "uuid", "email", "name", "status", "attributes", "created_at", "updated_at"},
+			{strconv.Itoa(profile.ID), profile.UUID.String(), profile.Email, profile.Name, string(profile.Status), string(attributes), formatTime(profile.CreatedAt), formatTime(profile.UpdatedAt)},
+		}},
+		{"subscriptions.csv", [][]string{{"name", "type", "subscription_status", "created_at"}}},
+		{"campaign_views.csv", [][]string{{"campaign", "views"}}},
+		{"link_clicks.csv", [][]string{{"url", "clicks"}}},
+	}
+	for _, subscription := range export.Subscriptions {
+		files[1].rows = append(files[1].rows, []string{subscription.Name, string(subscription.Type), subscription.SubscriptionStatus, formatTime(subscription.CreatedAt)})
+	}
+	for _, view := range export.CampaignViews {
+		files[2].rows = append(files[2].rows, []string{view.Campaign, strconv.Itoa(view.Views)})
+	}
+	for _, click := range export.LinkClicks {
+		files[3].rows = append(files[3].rows, []string{click.URL, strconv.Itoa(click.Clicks)})
+	}
+
+	archive := zip.NewWriter(w)
+	for _, file := range files {
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if err := csv.NewWriter(writer).WriteAll(file.rows); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+var exportTemplate = template.Must(template.New("export").Funcs(template.FuncMap{"time": formatTime}).Parse(`<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Data export for {{ .Profile.Email }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.8em; text-align: left; }
+</style>
+</head>
+<body>
+<h1>Data export for {{ .Profile.Email }}</h1>
+<h2>Profile</h2>
+<table>
+<tr><th>ID</th><td>{{ .Profile.ID }}</td></tr>
+<tr><th>UUID</th><td>{{ .Profile.UUID }}</td></tr>
+<tr><th>Email</th><td>{{ .Profile.Email }}</td></tr>
+<tr><th>Name</th><td>{{ .Profile.Name }}</td></tr>
+<tr><th>Status</th><td>{{ .Profile.Status }}</td></tr>
+<tr><th>Created</th><td>{{ time .Profile.CreatedAt }}</td></tr>
+<tr><th>Updated</th><td>{{ time .Profile.UpdatedAt }}</td></tr>
+{{- range $key, $value := .Profile.Attributes }}
+<tr><th>{{ $key }}</th><td>{{ $value }}</td></tr>
+{{- end }}
+</table>
+<h2>Subscriptions</h2>
+{{- if .Subscriptions }}
+<table>
+<tr><th>List</th><th>Type</th><th>Status</th><th>Subscribed</th></tr>
+{{- range .Subscriptions }}
+<tr><td>{{ .Name }}</td><td>{{ .Type }}</td><td>{{ .SubscriptionStatus }}</td><td>{{ time .CreatedAt }}</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p>None</p>
+{{- end }}
+<h2>Campaign views</h2>
+{{- if .CampaignViews }}
+<table>
+<tr><th>Campaign</th><th>Views</th></tr>
+{{- range .CampaignViews }}
+<tr><td>{{ .Campaign }}</td><td>{{ .Views }}</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p>None</p>
+{{- end }}
+<h2>Link clicks</h2>
+{{- if .LinkClicks }}
+<table>
+<tr><th>Link</th><th>Clicks</th></tr>
+{{- range .LinkClicks }}
+<tr><td>{{ .URL }}</td><td>{{ .Clicks }}</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p>None</p>
+{{- end }}
+</body>
+</html>
+`))
@@ -0,0 +1,95 @@
+package listmonkgo_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+const exportJSON = `{"email": "a@example.com",
+	"profile": [{"id": 1, "uuid": "0b0e5d2e-3f53-4b35-8a3c-6f1b1d2c3e4f", "email": "a@example.com", "name": "Ada <3", "status": "enabled", "attribs": {"city": "Paris"}, "created_at": "2024-01-02T03:04:05Z"}],
+	"subscriptions": [{"name": "News", "type": "public", "subscription_status": "confirmed", "created_at": "2024-01-02T03:04:05Z"}],
+	"campaign_views": [{"campaign": "March news", "views": 2}],
+	"link_clicks": [{"url": "https://example.com/?a=1,b", "clicks": 3}]}`
+
+func createExportClient(t *testing.T) *listmonkgo.Client {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/subscribers/{id}/export", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(exportJSON))
+	})
+	return createTestClient(t, mux)
+}
+
+func TestExportSubscriber(t *testing.T) {
+	export, err := createExportClient(t).ExportSubscriber(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if export.Profile.ID != 1 || export.Profile.Attributes["city"] != "Paris" || export.LinkClicks[0].Clicks != 3 || export.CampaignViews[0].Campaign != "March news" {
+		t.Errorf("unexpected export %+v", export)
+	}
+}
+
+func TestExportSubscriberTo(t *testing.T) {
+	client := createExportClient(t)
+	ctx := context.Background()
+
+	buffer := new(bytes.Buffer)
+	if err := client.ExportSubscriberTo(ctx, 1, buffer, listmonkgo.ExportFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	decoded := map[string]any{}
+	if err := json.Unmarshal(buffer.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if profile, ok := decoded["profile"].(map[string]any); !ok || profile["email"] != "a@example.com" || !strings.Contains(buffer.String(), "\n  ") {
+		t.Errorf("unexpected json export:\n%s", buffer)
+	}
+
+	buffer.Reset()
+	if err := client.ExportSubscriberTo(ctx, 1, buffer, listmonkgo.ExportFormatZip); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, file := range archive.File {
+		reader, _ := file.Open()
+		data, _ := io.ReadAll(reader)
+		files[file.Name] = string(data)
+	}
+	expected := map[string]string{
+		"profile.csv":        "id,uuid,email,name,status,attributes,created_at,updated_at\n1,0b0e5d2e-3f53-4b35-8a3c-6f1b1d2c3e4f,a@example.com,Ada <3,enabled,\"{\"\"city\"\":\"\"Paris\"\"}\",2024-01-02T03:04:05Z,\n",
+		"subscriptions.csv":  "name,type,subscription_status,created_at\nNews,public,confirmed,2024-01-02T03:04:05Z\n",
+		"campaign_views.csv": "campaign,views\nMarch news,2\n",
+		"link_clicks.csv":    "url,clicks\n\"https://example.com/?a=1,b\",3\n",
+	}
+	for name, content := range expected {
+		if files[name] != content {
+			t.Errorf("unexpected %s:\n%s", name, files[name])
+		}
+	}
+
+	buffer.Reset()
+	if err := client.ExportSubscriberTo(ctx, 1, buffer, listmonkgo.ExportFormatHTML); err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range []string{"<title>Data export for a@example.com</title>", "<td>Ada &lt;3</td>", "<tr><th>city</th><td>Paris</td></tr>", "<td>March news</td><td>2</td>"} {
+		if !strings.Contains(buffer.String(), part) {
+			t.Errorf("expected html export to contain %q:\n%s", part, buffer)
+		}
+	}
+
+	if err := client.ExportSubscriberTo(ctx, 1, buffer, "xml"); err == nil {
+		t.Error("expected an unsupported format to be rejected")
+	}
+}
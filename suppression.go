@@ -0,0 +1,87 @@
+package listmonkgo
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Hash of an email address as kept in suppression lists, the hex encoded SHA-256 of the trimmed and
+// lowercased address. The hash is unsalted so that lists can be checked without a key, which also makes it
+// pseudonymous rather than anonymous data.
+func HashEmail(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
+
+// Local list of hashed email addresses that must not be subscribed again, eg: after an erasure request.
+// Importers check addresses with Contains. The list is a file with one hash per line.
+type SuppressionList struct {
+	mu     sync.Mutex
+	path   string
+	hashes map[string]bool
+}
+
+// Open the list at the path, creating it on the first addition if it does not exist.
+func OpenSuppressionList(path string) (*SuppressionList, error) {
+	list := &SuppressionList{path: path, hashes: map[string]bool{}}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return list, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if hash := strings.TrimSpace(scanner.Text()); len(hash) > 0 {
+			list.hashes[hash] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Whether the email address is suppressed.
+func (l *SuppressionList) Contains(email string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.hashes[HashEmail(email)]
+}
+
+// Add the hash of the email address to the list.
+func (l *SuppressionList) Add(email string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	hash := HashEmail(email)
+	if l.hashes[hash] {
+		return nil
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(hash + "\n"); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	l.hashes[hash] = true
+	return nil
+}
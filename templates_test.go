@@ -0,0 +1,119 @@
+package listmonkgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+// Serves templates from memory, storing exactly what the client sends.
+type templateServer struct {
+	mu        sync.Mutex
+	templates map[int]map[string]any
+	sent      []map[string]any
+	// Respond to writes with a list like older listmonk versions.
+	legacy bool
+}
+
+func newTemplateServer() *templateServer {
+	return &templateServer{templates: map[int]map[string]any{}}
+}
+
+func (s *templateServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := 0
+	fmt.Sscanf(r.URL.Path, "/api/templates/%d", &id)
+	switch r.Method {
+	case "POST", "PUT":
+		payload := map[string]any{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, `{"message": "invalid payload"}`, http.StatusBadRequest)
+			return
+		}
+		s.sent = append(s.sent, payload)
+		if r.Method == "POST" {
+			id = len(s.templates) + 1
+		}
+		if _, ok := s.templates[id]; r.Method == "PUT" && !ok {
+			http.Error(w, `{"message": "template not found"}`, http.StatusNotFound)
+			return
+		}
+		template := map[string]any{"id": id, "is_default": false}
+		for key, value := range payload {
+			template[key] = value
+		}
+		s.templates[id] = template
+		if s.legacy {
+			json.NewEncoder(w).Encode(map[string]any{"data": []any{template}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": template})
+	case "GET":
+		json.NewEncoder(w).Encode(map[string]any{"data": s.templates[id]})
+	}
+}
+
+func TestTemplateRoundTrip(t *testing.T) {
+	server := newTemplateServer()
+	client := createTestClient(t, server)
+	ctx := context.Background()
+
+	params := &listmonkgo.CreateTemplateParams{
+		Name:    "Order shipped",
+		Type:    listmonkgo.TemplateTypeTx,
+		Subject: "Your order {{ .Tx.Data.order }} has shipped",
+		Body:    "<p>Hi {{ .Subscriber.Name }}</p>",
+	}
+	created, err := client.CreateTemplate(ctx, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.sent[0]["type"] != "tx" || server.sent[0]["subject"] != params.Subject || server.sent[0]["body"] != params.Body {
+		t.Errorf("unexpected payload %v", server.sent[0])
+	}
+	if _, ok := server.sent[0]["body_source"]; ok {
+		t.Error("expected empty body source to be omitted")
+	}
+
+	read, err := client.GetTemplate(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Name != params.Name || read.Type != params.Type || read.Subject != params.Subject || read.Body != params.Body {
+		t.Errorf("expected %+v to match %+v", read, params)
+	}
+
+	update := &listmonkgo.CreateTemplateParams{
+		Name:       "Visual",
+		Type:       listmonkgo.TemplateTypeCampaignVisual,
+		Body:       `<html>{{ template "content" . }}</html>`,
+		BodySource: `{"root":{}}`,
+	}
+	server.legacy = true
+	updated, err := client.UpdateTemplate(ctx, created.ID, update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ID != created.ID || updated.BodySource != update.BodySource {
+		t.Errorf("unexpected updated template %+v", updated)
+	}
+
+	read, err = client.GetTemplate(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Name != update.Name || read.Type != update.Type || read.Body != update.Body || read.BodySource != update.BodySource {
+		t.Errorf("expected %+v to match %+v", read, update)
+	}
+
+	if _, err := client.UpdateTemplate(ctx, 42, update); err == nil || err.Error() != "template not found" {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
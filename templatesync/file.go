@@ -0,0 +1,96 @@
+// Package templatesync keeps listmonk templates in sync with a directory of HTML files.
+package templatesync
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+	"gopkg.in/yaml.v3"
+)
+
+// Sidecar metadata read from a YAML file next to the template, eg: welcome.yaml for welcome.html.
+type Meta struct {
+	// Type of the template. Defaults to campaign.
+	Type listmonkgo.TemplateType `yaml:"type"`
+	// Subject line for the template (only for tx)
+	Subject string `yaml:"subject"`
+	// Whether the template should be set as the default.
+	Default bool `yaml:"default"`
+}
+
+type File struct {
+	Meta
+	// Name of the template, the file name without its extension.
+	Name string
+	// Path of the HTML file.
+	Path string
+	// HTML body of the template
+	Body string
+}
+
+// Read every *.html file in a directory along with its optional sidecar metadata.
+func ParseDir(dir string) ([]*File, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return nil, err
+	}
+
+	files := []*File{}
+	defaults := 0
+	for _, path := range paths {
+		file, err := parseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if file.Default {
+			defaults++
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no html files found in " + dir)
+	}
+	if defaults > 1 {
+		return nil, errors.New("only one template can be set as the default")
+	}
+	return files, nil
+}
+
+func parseFile(path string) (*File, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	file := &File{Name: filepath.Base(base), Path: path, Body: string(body)}
+
+	meta, err := os.ReadFile(base + ".yaml")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(meta, &file.Meta); err != nil {
+			return nil, fmt.Errorf("%s.yaml: %w", base, err)
+		}
+	}
+
+	switch file.Type {
+	case "":
+		file.Type = listmonkgo.TemplateTypeCampaign
+	case listmonkgo.TemplateTypeCampaign, listmonkgo.TemplateTypeCampaignVisual:
+	case listmonkgo.TemplateTypeTx:
+		if len(file.Subject) == 0 {
+			return nil, fmt.Errorf("%s: tx templates require a subject", path)
+		}
+		if file.Default {
+			return nil, fmt.Errorf("%s: tx templates cannot be the default", path)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unknown template type %q", path, file.Type)
+	}
+	return file, nil
+}
@@ -0,0 +1,216 @@
+package templatesync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+// Page size used when listing campaigns.
+const pageSize = 100
+
+type Action string
+
+const (
+	CreateAction    Action = "create"
+	UpdateAction    Action = "update"
+	UnchangedAction Action = "unchanged"
+	DeleteAction    Action = "delete"
+	// The template is not in the directory but cannot be deleted since campaigns reference it or it is the default.
+	KeepAction Action = "keep"
+)
+
+type Change struct {
+	Action Action
+	// Name of the template.
+	Name string
+	// Local file of the template, nil when the template is deleted or kept.
+	File *File
+	// ID of the existing template, zero when the template is created.
+	TemplateID int
+	// Fields of the template that differ from the file.
+	Fields []string
+	// Whether the template is set as the default once applied.
+	SetDefault bool
+	// Why a template is kept.
+	Reason string
+}
+
+type Plan struct {
+	Changes []Change
+}
+
+// Human readable report of the plan, one line per template.
+func (p *Plan) String() string {
+	builder := new(strings.Builder)
+	for _, change := range p.Changes {
+		suffix := ""
+		if change.SetDefault {
+			suffix = " and set as default"
+		}
+		switch change.Action {
+		case CreateAction:
+			fmt.Fprintf(builder, "+ %s: create %s template%s\n", change.Name, change.File.Type, suffix)
+		case UpdateAction:
+			fmt.Fprintf(builder, "~ %s: update template %d (%s)%s\n", change.Name, change.TemplateID, strings.Join(change.Fields, ", "), suffix)
+		case UnchangedAction:
+			if change.SetDefault {
+				fmt.Fprintf(builder, "~ %s: set template %d as default\n", change.Name, change.TemplateID)
+			} else {
+				fmt.Fprintf(builder, "  %s: template %d is up to date\n", change.Name, change.TemplateID)
+			}
+		case DeleteAction:
+			fmt.Fprintf(builder, "- %s: delete template %d\n", change.Name, change.TemplateID)
+		case KeepAction:
+			fmt.Fprintf(builder, "! %s: keep template %d, %s\n", change.Name, change.TemplateID, change.Reason)
+		}
+	}
+	return builder.String()
+}
+
+// Whether applying the plan would change anything.
+func (p *Plan) HasChanges() bool {
+	for _, change := range p.Changes {
+		if change.SetDefault || change.Action == CreateAction || change.Action == UpdateAction || change.Action == DeleteAction {
+			return true
+		}
+	}
+	return false
+}
+
+type Config struct {
+	// Delete templates that are not in the directory. Templates referenced by campaigns are never deleted.
+	Prune bool
+}
+
+type Syncer struct {
+	client *listmonkgo.Client
+	config Config
+}
+
+func New(client *listmonkgo.Client, config Config) *Syncer {
+	return &Syncer{client: client, config: config}
+}
+
+// Compare the files with the templates in listmonk by name without changing anything.
+func (s *Syncer) Plan(ctx context.Context, files []*File) (*Plan, error) {
+	templates, err := s.client.GetTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]*listmonkgo.Template{}
+	for i := range templates {
+		existing[templates[i].Name] = &templates[i]
+	}
+
+	plan := &Plan{}
+	local := map[string]bool{}
+	replacesDefault := false
+	for _, file := range files {
+		local[file.Name] = true
+		replacesDefault = replacesDefault || file.Default
+		template, ok := existing[file.Name]
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{Action: CreateAction, Name: file.Name, File: file, SetDefault: file.Default})
+			continue
+		}
+		if template.Type != file.Type {
+			return nil, fmt.Errorf("%s: template type cannot change from %s to %s", file.Name, template.Type, file.Type)
+		}
+
+		change := Change{Action: UnchangedAction, Name: file.Name, File: file, TemplateID: template.ID, SetDefault: file.Default && !template.IsDefault}
+		if template.Body != file.Body {
+			change.Fields = append(change.Fields, "body")
+		}
+		if template.Type == listmonkgo.TemplateTypeTx && template.Subject != file.Subject {
+			change.Fields = append(change.Fields, "subject")
+		}
+		if len(change.Fields) > 0 {
+			change.Action = UpdateAction
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	if !s.config.Prune {
+		return plan, nil
+	}
+
+	referenced, err := s.referenced(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		if local[template.Name] {
+			continue
+		}
+		change := Change{Action: DeleteAction, Name: template.Name, TemplateID: template.ID}
+		// Deletions are applied after the new default is set
+		if template.IsDefault && !replacesDefault {
+			change.Action, change.Reason = KeepAction, "it is the default"
+		} else if count := referenced[template.ID]; count > 0 {
+			change.Action, change.Reason = KeepAction, fmt.Sprintf("referenced by %d campaign(s)", count)
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	return plan, nil
+}
+
+// Create, update, set default and delete the templates in the plan. Unchanged and kept templates are skipped.
+func (s *Syncer) Apply(ctx context.Context, plan *Plan) error {
+	for i, change := range plan.Changes {
+		switch change.Action {
+		case CreateAction:
+			template, err := s.client.CreateTemplate(ctx, params(change.File))
+			if err != nil {
+				return fmt.Errorf("%s: %w", change.Name, err)
+			}
+			plan.Changes[i].TemplateID = template.ID
+		case UpdateAction:
+			if _, err := s.client.UpdateTemplate(ctx, change.TemplateID, params(change.File)); err != nil {
+				return fmt.Errorf("%s: %w", change.Name, err)
+			}
+		case DeleteAction:
+			if _, err := s.client.DeleteTemplate(ctx, change.TemplateID); err != nil {
+				return fmt.Errorf("%s: %w", change.Name, err)
+			}
+		}
+
+		if plan.Changes[i].SetDefault {
+			if _, err := s.client.SetDefaultTemplate(ctx, plan.Changes[i].TemplateID); err != nil {
+				return fmt.Errorf("%s: %w", change.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Count the campaigns that reference each template, including as their archive template.
+func (s *Syncer) referenced(ctx context.Context) (map[int]int, error) {
+	counts := map[int]int{}
+	for page := 1; ; page++ {
+		resp, err := s.client.GetCampaigns(ctx, &listmonkgo.GetCampaignParams{Page: page, PerPage: pageSize, NoBody: true})
+		if err != nil {
+			return nil, err
+		}
+		for _, campaign := range resp.Results {
+			counts[campaign.TemplateID]++
+			if campaign.ArchiveTemplateID != 0 && campaign.ArchiveTemplateID != campaign.TemplateID {
+				counts[campaign.ArchiveTemplateID]++
+			}
+		}
+		if len(resp.Results) == 0 || page*pageSize >= resp.Total {
+			return counts, nil
+		}
+	}
+}
+
+func params(file *File) *listmonkgo.CreateTemplateParams {
+	return &listmonkgo.CreateTemplateParams{
+		Name:    file.Name,
+		Type:    file.Type,
+		Subject: file.Subject,
+		Body:    file.Body,
+	}
+}
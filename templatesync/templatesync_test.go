@@ -0,0 +1,100 @@
+package templatesync_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+	"github.com/canpacis/listmonk-go/templatesync"
+)
+
+func TestSync(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"newsletter.html": `<html>{{ template "content" . }}</html>`,
+		"newsletter.yaml": "default: true\n",
+		"receipt.html":    "<p>Thanks</p>",
+		"receipt.yaml":    "type: tx\nsubject: Your receipt\n",
+		"welcome.html":    "<p>Welcome</p>",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	calls := []string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/templates", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [
+			{"id": 1, "name": "Default", "type": "campaign", "body": "old", "is_default": true},
+			{"id": 2, "name": "newsletter", "type": "campaign", "body": "<html>old</html>"},
+			{"id": 3, "name": "welcome", "type": "campaign", "body": "<p>Welcome</p>"},
+			{"id": 4, "name": "legacy", "type": "campaign", "body": "legacy"}
+		]}`))
+	})
+	mux.HandleFunc("GET /api/campaigns", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"results": [{"id": 1, "template_id": 4}], "total": 1}}`))
+	})
+	mux.HandleFunc("/api/templates/", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Method == "DELETE" {
+			w.Write([]byte(`{"data": true}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"id": 2}}`))
+	})
+	mux.HandleFunc("POST /api/templates", func(w http.ResponseWriter, r *http.Request) {
+		var params listmonkgo.CreateTemplateParams
+		json.NewDecoder(r.Body).Decode(&params)
+		calls = append(calls, fmt.Sprintf("POST %s %s %q", params.Name, params.Type, params.Subject))
+		w.Write([]byte(`{"data": {"id": 5}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	parsed, err := templatesync.ParseDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncer := templatesync.New(listmonkgo.New(listmonkgo.WithBaseURL(server.URL)), templatesync.Config{Prune: true})
+	plan, err := syncer.Plan(context.Background(), parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"~ newsletter: update template 2 (body) and set as default",
+		`+ receipt: create tx template`,
+		"  welcome: template 3 is up to date",
+		"- Default: delete template 1",
+		"! legacy: keep template 4, referenced by 1 campaign(s)",
+		"",
+	}, "\n")
+	if plan.String() != expected {
+		t.Errorf("unexpected plan:\n%s", plan)
+	}
+	if len(calls) != 0 {
+		t.Fatalf("planning must not change templates, got %v", calls)
+	}
+
+	if err := syncer.Apply(context.Background(), plan); err != nil {
+		t.Fatal(err)
+	}
+	expectedCalls := []string{
+		"PUT /api/templates/2",
+		"PUT /api/templates/2/default",
+		`POST receipt tx "Your receipt"`,
+		"DELETE /api/templates/1",
+	}
+	if strings.Join(calls, "\n") != strings.Join(expectedCalls, "\n") {
+		t.Errorf("unexpected calls:\n%s", strings.Join(calls, "\n"))
+	}
+}
@@ -0,0 +1,129 @@
+package listmonkgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Recipient of a transactional message, built with RecipientEmail, RecipientID, RecipientEmails or RecipientIDs.
+type Recipient struct {
+	email  string
+	id     int
+	emails []string
+	ids    []int
+}
+
+// Send to a single subscriber by email.
+func RecipientEmail(email string) Recipient {
+	return Recipient{email: email}
+}
+
+// Send to a single subscriber by ID.
+func RecipientID(id int) Recipient {
+	return Recipient{id: id}
+}
+
+// Send to many subscribers by email.
+func RecipientEmails(emails ...string) Recipient {
+	return Recipient{emails: emails}
+}
+
+// Send to many subscribers by ID.
+func RecipientIDs(ids ...int) Recipient {
+	return Recipient{ids: ids}
+}
+
+func (r Recipient) apply(params *SendTemplateParams) {
+	params.SubscriberEmail = r.email
+	params.SubscriberID = r.id
+	params.SubscriberEmails = r.emails
+	params.SubscriberIDs = r.ids
+}
+
+var ErrInvalidRecipient = errors.New("exactly one of subscriber_email, subscriber_id, subscriber_emails or subscriber_ids must be set")
+
+// Check that exactly one of the mutually exclusive recipient fields is set and a template is given.
+func (p *SendTemplateParams) Validate() error {
+	set := 0
+	if len(p.SubscriberEmail) > 0 {
+		set++
+	}
+	if p.SubscriberID != 0 {
+		set++
+	}
+	if len(p.SubscriberEmails) > 0 {
+		set++
+	}
+	if len(p.SubscriberIDs) > 0 {
+		set++
+	}
+	if set != 1 {
+		return ErrInvalidRecipient
+	}
+	if p.TemplateID == 0 {
+		return errors.New("template_id is required")
+	}
+	return nil
+}
+
+type TxOptions struct {
+	// Optional sender email.
+	FromEmail string
+	// Optional subject, overrides the subject of the template.
+	Subject string
+	// Optional alternate plain text body for HTML messages.
+	AltBody string
+	// Optional email headers.
+	Headers http.Header
+	// Messenger to send the message. Default is email.
+	Messenger string
+	// Email format options include html, markdown, and plain.
+	ContentType TemplateContentType
+	// Optional files to attach to the message.
+	Attachments []Attachment
+}
+
+// Handle to a transactional template that expects data of type T, available in the template as {{ .Tx.Data.* }}.
+type TxTemplate[T any] struct {
+	client *Client
+	id     int
+}
+
+// Create a typed handle to the transactional template with the given ID.
+func NewTxTemplate[T any](client *Client, id int) *TxTemplate[T] {
+	return &TxTemplate[T]{client: client, id: id}
+}
+
+// ID of the template.
+func (t *TxTemplate[T]) ID() int {
+	return t.id
+}
+
+// Build the params for a message without sending it.
+func (t *TxTemplate[T]) Params(recipient Recipient, data T, opts *TxOptions) (*SendTemplateParams, error) {
+	params := &SendTemplateParams{TemplateID: t.id, Data: data}
+	recipient.apply(params)
+	if opts != nil {
+		params.FromEmail = opts.FromEmail
+		params.Subject = opts.Subject
+		params.AltBody = opts.AltBody
+		params.Headers = opts.Headers
+		params.Messenger = opts.Messenger
+		params.ContentType = opts.ContentType
+		params.Attachments = opts.Attachments
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// Send the template to the recipient with the given data. Options are optional.
+func (t *TxTemplate[T]) Send(ctx context.Context, recipient Recipient, data T, opts *TxOptions) (bool, error) {
+	params, err := t.Params(recipient, data, opts)
+	if err != nil {
+		return false, err
+	}
+	return t.client.SendTemplate(ctx, params)
+}
@@ -0,0 +1,115 @@
+package listmonkgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	listmonkgo "github.com/canpacis/listmonk-go"
+)
+
+type orderShipped struct {
+	Order   string `json:"order"`
+	Carrier string `json:"carrier"`
+}
+
+func TestTxTemplateSend(t *testing.T) {
+	var sent struct {
+		SubscriberIDs []int        `json:"subscriber_ids"`
+		TemplateID    int          `json:"template_id"`
+		Data          orderShipped `json:"data"`
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/tx", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Error(err)
+			return
+		}
+		w.Write([]byte(`{"data": true}`))
+	})
+	client := createTestClient(t, mux)
+
+	shipped := listmonkgo.NewTxTemplate[orderShipped](client, 4)
+	ok, err := shipped.Send(context.Background(), listmonkgo.RecipientIDs(1, 2), orderShipped{Order: "A1", Carrier: "UPS"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || sent.TemplateID != 4 || len(sent.SubscriberIDs) != 2 || sent.Data.Order != "A1" || sent.Data.Carrier != "UPS" {
+		t.Errorf("unexpected payload %+v", sent)
+	}
+}
+
+func TestSendTemplateParamsValidate(t *testing.T) {
+	cases := []struct {
+		params listmonkgo.SendTemplateParams
+		valid  bool
+	}{
+		{listmonkgo.SendTemplateParams{TemplateID: 1, SubscriberEmail: "a@example.com"}, true},
+		{listmonkgo.SendTemplateParams{TemplateID: 1, SubscriberID: 1}, true},
+		{listmonkgo.SendTemplateParams{TemplateID: 1, SubscriberEmails: []string{"a@example.com"}}, true},
+		{listmonkgo.SendTemplateParams{TemplateID: 1, SubscriberIDs: []int{1}}, true},
+		{listmonkgo.SendTemplateParams{TemplateID: 1}, false},
+		{listmonkgo.SendTemplateParams{TemplateID: 1, SubscriberEmail: "a@example.com", SubscriberID: 1}, false},
+		{listmonkgo.SendTemplateParams{SubscriberID: 1}, false},
+	}
+	for i, c := range cases {
+		if err := c.params.Validate(); (err == nil) != c.valid {
+			t.Errorf("case %d: expected valid to be %v, got %v", i, c.valid, err)
+		}
+	}
+
+	_, err := listmonkgo.NewTxTemplate[orderShipped](nil, 4).Params(listmonkgo.RecipientEmails(), orderShipped{}, nil)
+	if !errors.Is(err, listmonkgo.ErrInvalidRecipient) {
+		t.Errorf("expected invalid recipient error, got %v", err)
+	}
+}
+
+func TestSendTemplateAttachments(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/tx", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Error(err)
+			return
+		}
+		params := map[string]any{}
+		if err := json.Unmarshal([]byte(r.FormValue("data")), &params); err != nil {
+			t.Error(err)
+			return
+		}
+		if params["subscriber_email"] != "ada@example.com" || params["template_id"] != float64(4) {
+			t.Errorf("unexpected params %v", params)
+		}
+
+		files := r.MultipartForm.File["file"]
+		expected := [][2]string{{"invoice.pdf", "application/pdf"}, {"notes.txt", "text/x-custom"}}
+		if len(files) != len(expected) {
+			t.Errorf("expected %d files, got %d", len(expected), len(files))
+			return
+		}
+		for i, file := range files {
+			if file.Filename != expected[i][0] || file.Header.Get("Content-Type") != expected[i][1] {
+				t.Errorf("unexpected file %s (%s)", file.Filename, file.Header.Get("Content-Type"))
+			}
+		}
+		w.Write([]byte(`{"data": true}`))
+	})
+	client := createTestClient(t, mux)
+
+	ok, err := client.SendTemplate(context.Background(), &listmonkgo.SendTemplateParams{
+		SubscriberEmail: "ada@example.com",
+		TemplateID:      4,
+		Attachments: []listmonkgo.Attachment{
+			{Name: "invoice.pdf", Reader: strings.NewReader("%PDF")},
+			{Name: "notes.txt", ContentType: "text/x-custom", Reader: strings.NewReader("notes")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("expected the message to be sent")
+	}
+}